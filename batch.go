@@ -0,0 +1,144 @@
+//go:build cgo && !nocgo
+
+package pkgcraft
+
+// #cgo pkg-config: pkgcraft
+// #include <pkgcraft.h>
+//
+// // Parse a buffer of NUL-terminated strings located at the given offsets,
+// // storing the resulting objects or errors at the related indices.
+// #define PARSE_BATCH(type, parse) \
+// 	for (size_t i = 0; i < len; i++) { \
+// 		type *obj = parse; \
+// 		objs[i] = obj; \
+// 		errs[i] = obj == NULL ? pkgcraft_error_last() : NULL; \
+// 	}
+//
+// static void parse_cpvs(char *buf, size_t *offsets, size_t len, Cpv **objs, PkgcraftError **errs) {
+// 	PARSE_BATCH(Cpv, pkgcraft_cpv_new(buf + offsets[i]))
+// }
+//
+// static void parse_deps(char *buf, size_t *offsets, size_t len, const Eapi *eapi, Dep **objs, PkgcraftError **errs) {
+// 	PARSE_BATCH(Dep, pkgcraft_dep_new(buf + offsets[i], eapi))
+// }
+//
+// static void parse_versions(char *buf, size_t *offsets, size_t len, Version **objs, PkgcraftError **errs) {
+// 	PARSE_BATCH(Version, pkgcraft_version_new(buf + offsets[i]))
+// }
+//
+// static void free_errors(PkgcraftError **errs, size_t len) {
+// 	for (size_t i = 0; i < len; i++) {
+// 		if (errs[i] != NULL) {
+// 			pkgcraft_error_free(errs[i]);
+// 		}
+// 	}
+// }
+import "C"
+
+import (
+	"unsafe"
+)
+
+// Buffer of NUL-terminated strings passed to C in a single call.
+type batchBuffer struct {
+	buf     []byte
+	offsets []C.size_t
+}
+
+func newBatchBuffer(vals []string) *batchBuffer {
+	size := 0
+	for _, s := range vals {
+		size += len(s) + 1
+	}
+
+	// use a non-empty allocation so a valid pointer can always be passed
+	b := &batchBuffer{make([]byte, 0, size+1), make([]C.size_t, len(vals)+1)}
+	for i, s := range vals {
+		b.offsets[i] = C.size_t(len(b.buf))
+		b.buf = append(b.buf, s...)
+		b.buf = append(b.buf, 0)
+	}
+	b.buf = append(b.buf, 0)
+	return b
+}
+
+func (self *batchBuffer) p() (*C.char, *C.size_t) {
+	return (*C.char)(unsafe.Pointer(&self.buf[0])), &self.offsets[0]
+}
+
+// Convert an array of C errors to Go errors, freeing the C errors.
+func batchErrors(c_errs []*C.PkgcraftError) []error {
+	errs := make([]error, len(c_errs))
+	failed := false
+	for i, err := range c_errs {
+		if err != nil {
+			errs[i] = &PkgcraftError{C.GoString(err.message)}
+			failed = true
+		}
+	}
+	if failed {
+		C.free_errors(&c_errs[0], C.size_t(len(c_errs)))
+	}
+	return errs
+}
+
+// Parse strings into Cpv objects using a single call into C, returning slices
+// of results and errors with entries related to each string's index.
+func ParseCpvs(vals []string) ([]*Cpv, []error) {
+	length := len(vals)
+	buf, offsets := newBatchBuffer(vals).p()
+	ptrs := make([]*C.Cpv, length+1)
+	c_errs := make([]*C.PkgcraftError, length+1)
+	C.parse_cpvs(buf, offsets, C.size_t(length), &ptrs[0], &c_errs[0])
+
+	cpvs := make([]*Cpv, length)
+	for i, ptr := range ptrs[:length] {
+		if ptr != nil {
+			cpvs[i], _ = cpvFromPtr(ptr)
+		}
+	}
+	return cpvs, batchErrors(c_errs[:length])
+}
+
+// Parse strings into Dep objects using a single call into C with a specific
+// EAPI, falling back to the latest EAPI if nil. Slices of results and errors
+// are returned with entries related to each string's index.
+func ParseDeps(vals []string, eapi *Eapi) ([]*Dep, []error) {
+	var eapi_ptr *C.Eapi
+	if eapi != nil {
+		eapi_ptr = eapi.ptr
+	}
+
+	length := len(vals)
+	buf, offsets := newBatchBuffer(vals).p()
+	ptrs := make([]*C.Dep, length+1)
+	c_errs := make([]*C.PkgcraftError, length+1)
+	C.parse_deps(buf, offsets, C.size_t(length), eapi_ptr, &ptrs[0], &c_errs[0])
+
+	deps := make([]*Dep, length)
+	for i, ptr := range ptrs[:length] {
+		if ptr != nil {
+			deps[i] = depPkgFromPtr(ptr)
+			deps[i].eapi = eapi
+		}
+	}
+	return deps, batchErrors(c_errs[:length])
+}
+
+// Parse strings into Version objects using a single call into C, returning
+// slices of results and errors with entries related to each string's index.
+func ParseVersions(vals []string) ([]*Version, []error) {
+	length := len(vals)
+	buf, offsets := newBatchBuffer(vals).p()
+	ptrs := make([]*C.Version, length+1)
+	c_errs := make([]*C.PkgcraftError, length+1)
+	C.parse_versions(buf, offsets, C.size_t(length), &ptrs[0], &c_errs[0])
+
+	versions := make([]*Version, length)
+	for i, ptr := range ptrs[:length] {
+		if ptr != nil {
+			versions[i], _ = versionFromPtr(ptr)
+		}
+	}
+	return versions, batchErrors(c_errs[:length])
+}
@@ -0,0 +1,33 @@
+//go:build !cgo || nocgo
+
+package pkgcraft
+
+// Parse strings into objects, returning slices of results and errors with
+// entries related to each string's index.
+func parseBatch[T any](vals []string, parse func(string) (*T, error)) ([]*T, []error) {
+	objs := make([]*T, len(vals))
+	errs := make([]error, len(vals))
+	for i, s := range vals {
+		objs[i], errs[i] = parse(s)
+	}
+	return objs, errs
+}
+
+// Parse strings into Cpv objects, returning slices of results and errors with
+// entries related to each string's index.
+func ParseCpvs(vals []string) ([]*Cpv, []error) {
+	return parseBatch(vals, NewCpv)
+}
+
+// Parse strings into Dep objects using a specific EAPI, falling back to the
+// latest EAPI if nil. Slices of results and errors are returned with entries
+// related to each string's index.
+func ParseDeps(vals []string, eapi *Eapi) ([]*Dep, []error) {
+	return parseBatch(vals, func(s string) (*Dep, error) { return newDep(s, eapi) })
+}
+
+// Parse strings into Version objects, returning slices of results and errors
+// with entries related to each string's index.
+func ParseVersions(vals []string) ([]*Version, []error) {
+	return parseBatch(vals, NewVersion)
+}
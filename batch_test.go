@@ -0,0 +1,73 @@
+package pkgcraft_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/pkgcraft/pkgcraft-go"
+)
+
+func TestParseCpvs(t *testing.T) {
+	// empty
+	cpvs, errs := ParseCpvs([]string{})
+	assert.Empty(t, cpvs)
+	assert.Empty(t, errs)
+
+	// valid and invalid
+	cpvs, errs = ParseCpvs([]string{"cat/pkg-1", "=cat/pkg-1", "", "a/b-2-r3"})
+	assert.Equal(t, len(cpvs), 4)
+	assert.Equal(t, len(errs), 4)
+	assert.Equal(t, cpvs[0].String(), "cat/pkg-1")
+	assert.Nil(t, errs[0])
+	assert.Nil(t, cpvs[1])
+	assert.NotNil(t, errs[1])
+	assert.Nil(t, cpvs[2])
+	assert.NotNil(t, errs[2])
+	assert.Equal(t, cpvs[3].String(), "a/b-2-r3")
+	assert.Nil(t, errs[3])
+
+	// errors match those from single object parsing
+	_, err := NewCpv("=cat/pkg-1")
+	assert.Equal(t, errs[1], err)
+}
+
+func TestParseDeps(t *testing.T) {
+	// valid and invalid
+	deps, errs := ParseDeps([]string{"cat/pkg", "cat/pkg-1", ">=cat/pkg-1::repo"}, nil)
+	assert.Equal(t, deps[0].String(), "cat/pkg")
+	assert.Nil(t, errs[0])
+	assert.Nil(t, deps[1])
+	assert.NotNil(t, errs[1])
+	assert.Equal(t, deps[2].String(), ">=cat/pkg-1::repo")
+	assert.Nil(t, errs[2])
+
+	// EAPI-specific
+	deps, errs = ParseDeps([]string{"cat/pkg", "cat/pkg::repo"}, EAPI_LATEST_OFFICIAL)
+	assert.NotNil(t, deps[0])
+	assert.Nil(t, errs[0])
+	assert.Nil(t, deps[1])
+	assert.NotNil(t, errs[1])
+}
+
+func TestParseVersions(t *testing.T) {
+	versions, errs := ParseVersions([]string{"1", "-1", ">=1.2_alpha3-r4"})
+	assert.Equal(t, versions[0].String(), "1")
+	assert.Nil(t, errs[0])
+	assert.Nil(t, versions[1])
+	assert.NotNil(t, errs[1])
+	assert.Equal(t, versions[2].String(), ">=1.2_alpha3-r4")
+	assert.Nil(t, errs[2])
+}
+
+func BenchmarkParseCpvs(b *testing.B) {
+	var vals []string
+	for i := 0; i < 1000; i++ {
+		vals = append(vals, fmt.Sprintf("cat/pkg-%d", i))
+	}
+	for i := 0; i < b.N; i++ {
+		cpvs, _ := ParseCpvs(vals)
+		assert.Equal(b, len(cpvs), 1000)
+	}
+}
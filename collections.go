@@ -0,0 +1,235 @@
+package pkgcraft
+
+import (
+	"sort"
+)
+
+// Types supporting hashing and comparisons via pkgcraft.
+type hashKey[T any] interface {
+	Hash() uint64
+	Cmp(other T) int
+}
+
+type hashEntry[K any, V any] struct {
+	key   K
+	value V
+}
+
+// Hash map using pkgcraft hashing and equality for its keys, the zero value is
+// an empty map ready to use.
+type hashMap[K hashKey[K], V any] struct {
+	buckets map[uint64][]hashEntry[K, V]
+	len     int
+}
+
+// Return the index of a key in its bucket, -1 if nonexistent.
+func (self *hashMap[K, V]) find(hash uint64, key K) int {
+	for i, entry := range self.buckets[hash] {
+		if entry.key.Cmp(key) == 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+func (self *hashMap[K, V]) get(key K) (V, bool) {
+	hash := key.Hash()
+	if i := self.find(hash, key); i >= 0 {
+		return self.buckets[hash][i].value, true
+	}
+	var value V
+	return value, false
+}
+
+// Set the value for a key, returning true if the key was newly added.
+func (self *hashMap[K, V]) set(key K, value V) bool {
+	if self.buckets == nil {
+		self.buckets = make(map[uint64][]hashEntry[K, V])
+	}
+	hash := key.Hash()
+	if i := self.find(hash, key); i >= 0 {
+		self.buckets[hash][i].value = value
+		return false
+	}
+	self.buckets[hash] = append(self.buckets[hash], hashEntry[K, V]{key, value})
+	self.len++
+	return true
+}
+
+// Remove a key, returning true if it existed.
+func (self *hashMap[K, V]) remove(key K) bool {
+	hash := key.Hash()
+	i := self.find(hash, key)
+	if i < 0 {
+		return false
+	}
+	bucket := self.buckets[hash]
+	if len(bucket) == 1 {
+		delete(self.buckets, hash)
+	} else {
+		self.buckets[hash] = append(bucket[:i:i], bucket[i+1:]...)
+	}
+	self.len--
+	return true
+}
+
+// Return all entries sorted by key.
+func (self *hashMap[K, V]) entries() []hashEntry[K, V] {
+	entries := make([]hashEntry[K, V], 0, self.len)
+	for _, bucket := range self.buckets {
+		entries = append(entries, bucket...)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].key.Cmp(entries[j].key) < 0 })
+	return entries
+}
+
+func (self *hashMap[K, V]) keys() []K {
+	var keys []K
+	for _, entry := range self.entries() {
+		keys = append(keys, entry.key)
+	}
+	return keys
+}
+
+// Set of Cpv objects using pkgcraft hashing and equality, the zero value is an
+// empty set ready to use.
+type CpvSet struct {
+	m hashMap[*Cpv, struct{}]
+}
+
+// Create a new Cpv set from the given values.
+func NewCpvSet(cpvs ...*Cpv) *CpvSet {
+	set := &CpvSet{}
+	for _, cpv := range cpvs {
+		set.Add(cpv)
+	}
+	return set
+}
+
+// Add a Cpv to the set, returning true if it wasn't already present.
+func (self *CpvSet) Add(cpv *Cpv) bool {
+	return self.m.set(cpv, struct{}{})
+}
+
+// Remove a Cpv from the set, returning true if it was present.
+func (self *CpvSet) Remove(cpv *Cpv) bool {
+	return self.m.remove(cpv)
+}
+
+// Determine if the set contains a Cpv.
+func (self *CpvSet) Contains(cpv *Cpv) bool {
+	_, ok := self.m.get(cpv)
+	return ok
+}
+
+// Return the number of Cpvs in the set.
+func (self *CpvSet) Len() int {
+	return self.m.len
+}
+
+// Return the set's values in sorted order.
+func (self *CpvSet) Values() []*Cpv {
+	return self.m.keys()
+}
+
+// Set of Dep objects using pkgcraft hashing and equality, the zero value is an
+// empty set ready to use.
+type DepSet struct {
+	m hashMap[*Dep, struct{}]
+}
+
+// Create a new Dep set from the given values.
+func NewDepSet(deps ...*Dep) *DepSet {
+	set := &DepSet{}
+	for _, dep := range deps {
+		set.Add(dep)
+	}
+	return set
+}
+
+// Add a Dep to the set, returning true if it wasn't already present.
+func (self *DepSet) Add(dep *Dep) bool {
+	return self.m.set(dep, struct{}{})
+}
+
+// Remove a Dep from the set, returning true if it was present.
+func (self *DepSet) Remove(dep *Dep) bool {
+	return self.m.remove(dep)
+}
+
+// Determine if the set contains a Dep.
+func (self *DepSet) Contains(dep *Dep) bool {
+	_, ok := self.m.get(dep)
+	return ok
+}
+
+// Return the number of Deps in the set.
+func (self *DepSet) Len() int {
+	return self.m.len
+}
+
+// Return the set's values in sorted order.
+func (self *DepSet) Values() []*Dep {
+	return self.m.keys()
+}
+
+// Map keyed by Cpn objects using pkgcraft hashing and equality, the zero value
+// is an empty map ready to use.
+type CpnMap[V any] struct {
+	m hashMap[*Cpn, V]
+}
+
+// Create a new, empty Cpn map.
+func NewCpnMap[V any]() *CpnMap[V] {
+	return &CpnMap[V]{}
+}
+
+// Return the value for a Cpn and whether it exists.
+func (self *CpnMap[V]) Get(cpn *Cpn) (V, bool) {
+	return self.m.get(cpn)
+}
+
+// Set the value for a Cpn, returning true if the Cpn was newly added.
+func (self *CpnMap[V]) Set(cpn *Cpn, value V) bool {
+	return self.m.set(cpn, value)
+}
+
+// Remove a Cpn from the map, returning true if it existed.
+func (self *CpnMap[V]) Delete(cpn *Cpn) bool {
+	return self.m.remove(cpn)
+}
+
+// Determine if the map contains a Cpn.
+func (self *CpnMap[V]) Contains(cpn *Cpn) bool {
+	_, ok := self.m.get(cpn)
+	return ok
+}
+
+// Return the number of entries in the map.
+func (self *CpnMap[V]) Len() int {
+	return self.m.len
+}
+
+// Return the map's keys in sorted order.
+func (self *CpnMap[V]) Keys() []*Cpn {
+	return self.m.keys()
+}
+
+// Return the map's values ordered by their related keys.
+func (self *CpnMap[V]) Values() []V {
+	var values []V
+	for _, entry := range self.m.entries() {
+		values = append(values, entry.value)
+	}
+	return values
+}
+
+// Call a function for each entry in the map ordered by key, stopping early if
+// it returns false.
+func (self *CpnMap[V]) Range(f func(cpn *Cpn, value V) bool) {
+	for _, entry := range self.m.entries() {
+		if !f(entry.key, entry.value) {
+			return
+		}
+	}
+}
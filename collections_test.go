@@ -0,0 +1,122 @@
+package pkgcraft_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/pkgcraft/pkgcraft-go"
+)
+
+func TestCpvSet(t *testing.T) {
+	c1, _ := NewCpv("cat/pkg-2")
+	c2, _ := NewCpv("cat/pkg-1")
+	c3, _ := NewCpv("cat/pkg-1-r0")
+	c4, _ := NewCpv("a/b-1")
+
+	set := NewCpvSet(c1, c2)
+	assert.Equal(t, set.Len(), 2)
+
+	// semantically equal values are deduplicated
+	assert.False(t, set.Add(c3))
+	assert.True(t, set.Contains(c3))
+	assert.Equal(t, set.Len(), 2)
+	assert.True(t, set.Add(c4))
+
+	// sorted iteration
+	var vals []string
+	for _, cpv := range set.Values() {
+		vals = append(vals, cpv.String())
+	}
+	assert.Equal(t, vals, []string{"a/b-1", "cat/pkg-1", "cat/pkg-2"})
+
+	// removal
+	assert.True(t, set.Remove(c3))
+	assert.False(t, set.Remove(c2))
+	assert.False(t, set.Contains(c2))
+	assert.Equal(t, set.Len(), 2)
+
+	// empty
+	set = NewCpvSet()
+	assert.Equal(t, set.Len(), 0)
+	assert.Empty(t, set.Values())
+}
+
+func TestDepSet(t *testing.T) {
+	d1, _ := NewDep("=cat/pkg-1")
+	d2, _ := NewDep("=cat/pkg-1-r0")
+	d3, _ := NewDep("cat/pkg")
+
+	set := NewDepSet(d1, d2, d3)
+	assert.Equal(t, set.Len(), 2)
+	assert.True(t, set.Contains(d2))
+	assert.Equal(t, set.Values()[0].String(), "cat/pkg")
+
+	assert.True(t, set.Remove(d2))
+	assert.False(t, set.Contains(d1))
+	assert.Equal(t, set.Len(), 1)
+}
+
+func TestCpnMap(t *testing.T) {
+	c1, _ := NewCpn("cat/pkg")
+	c2, _ := NewCpn("cat/pkg")
+	c3, _ := NewCpn("a/b")
+
+	m := NewCpnMap[int]()
+	assert.True(t, m.Set(c1, 1))
+	assert.False(t, m.Set(c2, 2))
+	assert.True(t, m.Set(c3, 3))
+	assert.Equal(t, m.Len(), 2)
+
+	val, ok := m.Get(c1)
+	assert.True(t, ok)
+	assert.Equal(t, val, 2)
+	assert.True(t, m.Contains(c2))
+
+	// sorted iteration
+	keys := m.Keys()
+	assert.Equal(t, keys[0].String(), "a/b")
+	assert.Equal(t, keys[1].String(), "cat/pkg")
+	assert.Equal(t, m.Values(), []int{3, 2})
+	var seen []string
+	m.Range(func(cpn *Cpn, _ int) bool {
+		seen = append(seen, cpn.String())
+		return false
+	})
+	assert.Equal(t, seen, []string{"a/b"})
+
+	// removal
+	assert.True(t, m.Delete(c2))
+	assert.False(t, m.Delete(c1))
+	_, ok = m.Get(c1)
+	assert.False(t, ok)
+	assert.Equal(t, m.Len(), 1)
+}
+
+func TestCollectionsZeroValue(t *testing.T) {
+	cpv, _ := NewCpv("cat/pkg-1")
+	dep, _ := NewDep("cat/pkg")
+	cpn, _ := NewCpn("cat/pkg")
+
+	var cpvs CpvSet
+	assert.False(t, cpvs.Contains(cpv))
+	assert.False(t, cpvs.Remove(cpv))
+	assert.Empty(t, cpvs.Values())
+	assert.True(t, cpvs.Add(cpv))
+	assert.True(t, cpvs.Contains(cpv))
+	assert.Equal(t, cpvs.Len(), 1)
+
+	deps := DepSet{}
+	assert.False(t, deps.Contains(dep))
+	assert.True(t, deps.Add(dep))
+	assert.Equal(t, deps.Len(), 1)
+
+	m := CpnMap[int]{}
+	_, ok := m.Get(cpn)
+	assert.False(t, ok)
+	assert.False(t, m.Delete(cpn))
+	assert.Empty(t, m.Keys())
+	assert.True(t, m.Set(cpn, 1))
+	val, _ := m.Get(cpn)
+	assert.Equal(t, val, 1)
+}
@@ -1,3 +1,5 @@
+//go:build cgo && !nocgo
+
 package pkgcraft
 
 // #cgo pkg-config: pkgcraft
@@ -1,3 +1,5 @@
+//go:build cgo && !nocgo
+
 package pkgcraft
 
 // #cgo pkg-config: pkgcraft
@@ -5,6 +7,7 @@ package pkgcraft
 import "C"
 
 import (
+	"database/sql/driver"
 	"runtime"
 	"unsafe"
 )
@@ -15,6 +18,8 @@ type Cpn struct {
 	_category string
 	_package  string
 	_hash     uint64
+	// owner of the C pointer for objects populated in place
+	_owner *Cpn
 }
 
 type cpnPtr interface {
@@ -24,7 +29,7 @@ type cpnPtr interface {
 func cpnFromPtr(ptr *C.Cpn) (*Cpn, error) {
 	if ptr != nil {
 		cpn := &Cpn{ptr: ptr}
-		runtime.SetFinalizer(cpn, func(self *Cpn) { C.pkgcraft_cpn_free(self.ptr) })
+		runtime.SetFinalizer(cpn, func(*Cpn) { C.pkgcraft_cpn_free(ptr) })
 		return cpn, nil
 	} else {
 		return nil, newPkgcraftError()
@@ -76,6 +81,40 @@ func (self *Cpn) Hash() uint64 {
 	return self._hash
 }
 
+func (self *Cpn) MarshalText() ([]byte, error) {
+	if self == nil || self.ptr == nil {
+		return []byte{}, nil
+	}
+	return []byte(self.String()), nil
+}
+
+func (self *Cpn) UnmarshalText(data []byte) error {
+	cpn, err := NewCpn(string(data))
+	if err != nil {
+		return err
+	}
+
+	populate(self, cpn, &self._owner)
+	return nil
+}
+
+// Implement the sql.Scanner interface, NULL values are scanned as the zero value.
+func (self *Cpn) Scan(src interface{}) error {
+	if src == nil {
+		*self = Cpn{}
+		return nil
+	}
+	return scanText(self, src)
+}
+
+// Implement the driver.Valuer interface, the zero value is stored as NULL.
+func (self *Cpn) Value() (driver.Value, error) {
+	if self == nil || self.ptr == nil {
+		return nil, nil
+	}
+	return self.String(), nil
+}
+
 // Compare two Cpns returning -1, 0, or 1 if the first is less than, equal to,
 // or greater than the second, respectively.
 func (self *Cpn) Cmp(other *Cpn) int {
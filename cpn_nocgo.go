@@ -0,0 +1,120 @@
+//go:build !cgo || nocgo
+
+package pkgcraft
+
+import (
+	"database/sql/driver"
+	"regexp"
+	"strings"
+)
+
+var categoryRe = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9+_.-]*$`)
+var packageRe = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9+_-]*$`)
+var packageVersionRe = regexp.MustCompile(`-\d+(?:\.\d+)*[a-z]?(?:_(?:alpha|beta|pre|rc|p)\d*)*(?:-r\d+)?$`)
+
+// Verify a string is a valid category name.
+func validCategory(s string) bool {
+	return categoryRe.MatchString(s)
+}
+
+// Verify a string is a valid package name, disallowing those ending in a
+// hyphen followed by anything that could be a version.
+func validPackage(s string) bool {
+	if !packageRe.MatchString(s) {
+		return false
+	}
+	for i := strings.Index(s, "-"); i >= 0; i = nextIndex(s, "-", i) {
+		if packageVersionRe.MatchString(s[i:]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Return the index of the next instance of a substring after a given index.
+func nextIndex(s, substr string, i int) int {
+	if j := strings.Index(s[i+1:], substr); j >= 0 {
+		return i + 1 + j
+	}
+	return -1
+}
+
+// Split a string into its category and remaining components.
+func splitCategory(s string) (string, string, bool) {
+	category, rest, found := strings.Cut(s, "/")
+	return category, rest, found && validCategory(category)
+}
+
+type Cpn struct {
+	category string
+	pkg      string
+}
+
+// Parse a string into a Cpn object.
+func NewCpn(s string) (*Cpn, error) {
+	category, pkg, ok := splitCategory(s)
+	if !ok || !validPackage(pkg) {
+		return nil, newParseError("invalid cpn: %s", s)
+	}
+	return &Cpn{category, pkg}, nil
+}
+
+// Return an Cpn's category.
+func (self *Cpn) Category() string {
+	return self.category
+}
+
+// Return a Cpn's package name.
+func (self *Cpn) Package() string {
+	return self.pkg
+}
+
+func (self *Cpn) String() string {
+	return self.category + "/" + self.pkg
+}
+
+func (self *Cpn) Hash() uint64 {
+	return hashString(self.String())
+}
+
+func (self *Cpn) MarshalText() ([]byte, error) {
+	if self == nil || self.category == "" {
+		return []byte{}, nil
+	}
+	return []byte(self.String()), nil
+}
+
+func (self *Cpn) UnmarshalText(data []byte) error {
+	cpn, err := NewCpn(string(data))
+	if err != nil {
+		return err
+	}
+	*self = *cpn
+	return nil
+}
+
+// Implement the sql.Scanner interface, NULL values are scanned as the zero value.
+func (self *Cpn) Scan(src interface{}) error {
+	if src == nil {
+		*self = Cpn{}
+		return nil
+	}
+	return scanText(self, src)
+}
+
+// Implement the driver.Valuer interface, the zero value is stored as NULL.
+func (self *Cpn) Value() (driver.Value, error) {
+	if self == nil || self.category == "" {
+		return nil, nil
+	}
+	return self.String(), nil
+}
+
+// Compare two Cpns returning -1, 0, or 1 if the first is less than, equal to,
+// or greater than the second, respectively.
+func (self *Cpn) Cmp(other *Cpn) int {
+	if c := cmpStrings(self.category, other.category); c != 0 {
+		return c
+	}
+	return cmpStrings(self.pkg, other.pkg)
+}
@@ -1,6 +1,7 @@
 package pkgcraft_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -46,3 +47,61 @@ func TestCpnCmp(t *testing.T) {
 	c2, _ = NewCpn("a/b")
 	assert.Equal(t, c1.Cmp(c2), 1)
 }
+
+func TestCpnMarshal(t *testing.T) {
+	cpn, _ := NewCpn("cat/pkg")
+
+	// text
+	data, err := cpn.MarshalText()
+	assert.Nil(t, err)
+	assert.Equal(t, string(data), "cat/pkg")
+	var c Cpn
+	assert.Nil(t, c.UnmarshalText(data))
+	assert.Equal(t, c.Cmp(cpn), 0)
+
+	// JSON
+	data, err = json.Marshal(map[string]*Cpn{"cpn": cpn})
+	assert.Nil(t, err)
+	assert.Equal(t, string(data), `{"cpn":"cat/pkg"}`)
+	var m map[string]*Cpn
+	assert.Nil(t, json.Unmarshal(data, &m))
+	assert.Equal(t, m["cpn"].Cmp(cpn), 0)
+
+	// zero values
+	data, err = json.Marshal(&struct{ Cpn Cpn }{})
+	assert.Nil(t, err)
+	assert.Equal(t, string(data), `{"Cpn":""}`)
+	data, err = (*Cpn)(nil).MarshalText()
+	assert.Nil(t, err)
+	assert.Equal(t, string(data), "")
+
+	// invalid
+	assert.NotNil(t, json.Unmarshal([]byte(`{"cpn":"cat/pkg-1"}`), &m))
+}
+
+func TestCpnSql(t *testing.T) {
+	cpn, _ := NewCpn("cat/pkg")
+	val, err := cpn.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, val, "cat/pkg")
+
+	var c Cpn
+	assert.Nil(t, c.Scan("cat/pkg"))
+	assert.Equal(t, c.Cmp(cpn), 0)
+	assert.Nil(t, c.Scan([]byte("cat/pkg")))
+	assert.Equal(t, c.Cmp(cpn), 0)
+
+	// NULL values
+	val, err = (*Cpn)(nil).Value()
+	assert.Nil(t, err)
+	assert.Nil(t, val)
+	val, err = (&Cpn{}).Value()
+	assert.Nil(t, err)
+	assert.Nil(t, val)
+	assert.Nil(t, c.Scan(nil))
+	assert.Equal(t, c, Cpn{})
+
+	// invalid
+	assert.NotNil(t, c.Scan("cat/pkg-1"))
+	assert.NotNil(t, c.Scan(1))
+}
@@ -1,3 +1,5 @@
+//go:build cgo && !nocgo
+
 package pkgcraft
 
 // #cgo pkg-config: pkgcraft
@@ -5,6 +7,7 @@ package pkgcraft
 import "C"
 
 import (
+	"database/sql/driver"
 	"runtime"
 	"unsafe"
 )
@@ -16,6 +19,9 @@ type Cpv struct {
 	_package  string
 	_version  *Version
 	_hash     uint64
+	_key      string
+	// owner of the C pointer for objects populated in place
+	_owner *Cpv
 }
 
 type cpvPtr interface {
@@ -25,7 +31,7 @@ type cpvPtr interface {
 func cpvFromPtr(ptr *C.Cpv) (*Cpv, error) {
 	if ptr != nil {
 		cpv := &Cpv{ptr: ptr}
-		runtime.SetFinalizer(cpv, func(self *Cpv) { C.pkgcraft_cpv_free(self.ptr) })
+		runtime.SetFinalizer(cpv, func(*Cpv) { C.pkgcraft_cpv_free(ptr) })
 		return cpv, nil
 	} else {
 		return nil, newPkgcraftError()
@@ -150,12 +156,59 @@ func (self *Cpv) Hash() uint64 {
 	return self._hash
 }
 
+func (self *Cpv) MarshalText() ([]byte, error) {
+	if self == nil || self.ptr == nil {
+		return []byte{}, nil
+	}
+	return []byte(self.String()), nil
+}
+
+func (self *Cpv) UnmarshalText(data []byte) error {
+	cpv, err := NewCpv(string(data))
+	if err != nil {
+		return err
+	}
+
+	populate(self, cpv, &self._owner)
+	return nil
+}
+
+// Implement the sql.Scanner interface, NULL values are scanned as the zero value.
+func (self *Cpv) Scan(src interface{}) error {
+	if src == nil {
+		*self = Cpv{}
+		return nil
+	}
+	return scanText(self, src)
+}
+
+// Implement the driver.Valuer interface, the zero value is stored as NULL.
+func (self *Cpv) Value() (driver.Value, error) {
+	if self == nil || self.ptr == nil {
+		return nil, nil
+	}
+	return self.String(), nil
+}
+
 // Compare two deps returning -1, 0, or 1 if the first is less than, equal to,
 // or greater than the second, respectively.
 func (self *Cpv) Cmp(other *Cpv) int {
 	return int(C.pkgcraft_cpv_cmp(self.ptr, other.ptr))
 }
 
+// Return a byte string that sorts bytewise identically to Cmp(). This allows
+// sorting large numbers of Cpvs natively in Go.
+func (self *Cpv) SortKey() []byte {
+	return []byte(self.sortKey())
+}
+
+func (self *Cpv) sortKey() string {
+	if self._key == "" {
+		self._key = self.Category() + "\x00" + self.Package() + "\x00" + self.Version().sortKey()
+	}
+	return self._key
+}
+
 // Determine if two Cpv or Dep objects intersect.
 func (self *Cpv) Intersects(other interface{}) bool {
 	switch other := other.(type) {
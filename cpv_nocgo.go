@@ -0,0 +1,178 @@
+//go:build !cgo || nocgo
+
+package pkgcraft
+
+import (
+	"database/sql/driver"
+	"strings"
+)
+
+// Split a package and version string, e.g. "pkg-1-r2", into its package name
+// and version using the first hyphen resulting in valid components.
+func splitPackageVersion(s string) (string, string, bool) {
+	for i := strings.Index(s, "-"); i >= 0; i = nextIndex(s, "-", i) {
+		if validPackage(s[:i]) && versionRe.MatchString(s[i+1:]) {
+			return s[:i], s[i+1:], true
+		}
+	}
+	return "", "", false
+}
+
+type Cpv struct {
+	category string
+	pkg      string
+	version  *Version
+	// cached fields
+	_key string
+}
+
+// Parse a string into a Cpv object, optionally allowing a version operator
+// suffix, i.e. a glob.
+func parseCpv(s string, op Operator) (*Cpv, error) {
+	category, rest, ok := splitCategory(s)
+	if !ok {
+		return nil, newParseError("invalid cpv: %s", s)
+	}
+	pkg, ver_str, ok := splitPackageVersion(rest)
+	if !ok {
+		return nil, newParseError("invalid cpv: %s", s)
+	}
+	if op == OperatorNone && strings.HasSuffix(ver_str, "*") {
+		return nil, newParseError("invalid cpv: %s", s)
+	}
+	version, err := NewVersion(op.String() + ver_str)
+	if err != nil || (op == OperatorNone && version.op != OperatorNone) {
+		return nil, newParseError("invalid cpv: %s", s)
+	}
+	return &Cpv{category: category, pkg: pkg, version: version}, nil
+}
+
+// Parse a string into a Cpv object.
+func NewCpv(s string) (*Cpv, error) {
+	return parseCpv(s, OperatorNone)
+}
+
+// Return an Cpv's category.
+func (self *Cpv) Category() string {
+	return self.category
+}
+
+// Return a Cpv's package name.
+func (self *Cpv) Package() string {
+	return self.pkg
+}
+
+// Return a Cpv's version.
+func (self *Cpv) Version() *Version {
+	return self.version
+}
+
+// Return a Cpv's revision.
+func (self *Cpv) Revision() *Revision {
+	return self.version.Revision()
+}
+
+// Return a Cpv's package and version.
+func (self *Cpv) P() string {
+	return self.pkg + "-" + self.Pv()
+}
+
+// Return a Cpv's package, version, and revision.
+func (self *Cpv) Pf() string {
+	return self.pkg + "-" + self.Pvr()
+}
+
+// Return a Cpv's revision.
+func (self *Cpv) Pr() string {
+	if rev := self.Revision().String(); rev != "" {
+		return "r" + rev
+	}
+	return "r0"
+}
+
+// Return a Cpv's version.
+func (self *Cpv) Pv() string {
+	return self.version.base(false)
+}
+
+// Return a Cpv's version and revision.
+func (self *Cpv) Pvr() string {
+	return self.version.base(true)
+}
+
+// Return a Cpv object's Cpn.
+func (self *Cpv) Cpn() *Cpn {
+	return &Cpn{self.category, self.pkg}
+}
+
+func (self *Cpv) String() string {
+	return self.category + "/" + self.Pf()
+}
+
+func (self *Cpv) Hash() uint64 {
+	return hashString(self.sortKey())
+}
+
+func (self *Cpv) MarshalText() ([]byte, error) {
+	if self == nil || self.category == "" {
+		return []byte{}, nil
+	}
+	return []byte(self.String()), nil
+}
+
+func (self *Cpv) UnmarshalText(data []byte) error {
+	cpv, err := NewCpv(string(data))
+	if err != nil {
+		return err
+	}
+	*self = *cpv
+	return nil
+}
+
+// Implement the sql.Scanner interface, NULL values are scanned as the zero value.
+func (self *Cpv) Scan(src interface{}) error {
+	if src == nil {
+		*self = Cpv{}
+		return nil
+	}
+	return scanText(self, src)
+}
+
+// Implement the driver.Valuer interface, the zero value is stored as NULL.
+func (self *Cpv) Value() (driver.Value, error) {
+	if self == nil || self.category == "" {
+		return nil, nil
+	}
+	return self.String(), nil
+}
+
+// Compare two deps returning -1, 0, or 1 if the first is less than, equal to,
+// or greater than the second, respectively.
+func (self *Cpv) Cmp(other *Cpv) int {
+	return cmpStrings(self.sortKey(), other.sortKey())
+}
+
+// Return a byte string that sorts bytewise identically to Cmp(). This allows
+// sorting large numbers of Cpvs natively in Go.
+func (self *Cpv) SortKey() []byte {
+	return []byte(self.sortKey())
+}
+
+func (self *Cpv) sortKey() string {
+	if self._key == "" {
+		self._key = self.Category() + "\x00" + self.Package() + "\x00" + self.Version().sortKey()
+	}
+	return self._key
+}
+
+// Determine if two Cpv or Dep objects intersect.
+func (self *Cpv) Intersects(other interface{}) bool {
+	switch other := other.(type) {
+	case *Cpv:
+		return self.Cmp(other) == 0
+	case *Dep:
+		return other.Intersects(self)
+	default:
+		return false
+	}
+}
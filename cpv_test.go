@@ -1,6 +1,7 @@
 package pkgcraft_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -62,3 +63,65 @@ func TestCpvCmp(t *testing.T) {
 	c2, _ = NewCpv("cat/pkg-1")
 	assert.Equal(t, c1.Cmp(c2), 1)
 }
+
+func TestCpvMarshal(t *testing.T) {
+	cpv, _ := NewCpv("cat/pkg-1-r2")
+
+	// text
+	data, err := cpv.MarshalText()
+	assert.Nil(t, err)
+	assert.Equal(t, string(data), "cat/pkg-1-r2")
+	var c Cpv
+	assert.Nil(t, c.UnmarshalText(data))
+	assert.Equal(t, c.Cmp(cpv), 0)
+
+	// reusing existing objects
+	assert.Nil(t, c.UnmarshalText([]byte("cat/pkg-2")))
+	assert.Equal(t, c.String(), "cat/pkg-2")
+
+	// JSON
+	data, err = json.Marshal([]*Cpv{cpv})
+	assert.Nil(t, err)
+	assert.Equal(t, string(data), `["cat/pkg-1-r2"]`)
+	var cpvs []*Cpv
+	assert.Nil(t, json.Unmarshal(data, &cpvs))
+	assert.Equal(t, cpvs[0].Cmp(cpv), 0)
+
+	// zero values
+	data, err = json.Marshal(&struct{ Cpv Cpv }{})
+	assert.Nil(t, err)
+	assert.Equal(t, string(data), `{"Cpv":""}`)
+	data, err = (*Cpv)(nil).MarshalText()
+	assert.Nil(t, err)
+	assert.Equal(t, string(data), "")
+
+	// invalid
+	assert.NotNil(t, json.Unmarshal([]byte(`["=cat/pkg-1"]`), &cpvs))
+}
+
+func TestCpvSql(t *testing.T) {
+	cpv, _ := NewCpv("cat/pkg-1-r2")
+	val, err := cpv.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, val, "cat/pkg-1-r2")
+
+	var c Cpv
+	assert.Nil(t, c.Scan("cat/pkg-1-r2"))
+	assert.Equal(t, c.Cmp(cpv), 0)
+	assert.Nil(t, c.Scan([]byte("cat/pkg-1-r2")))
+	assert.Equal(t, c.Cmp(cpv), 0)
+
+	// NULL values
+	val, err = (*Cpv)(nil).Value()
+	assert.Nil(t, err)
+	assert.Nil(t, val)
+	val, err = (&Cpv{}).Value()
+	assert.Nil(t, err)
+	assert.Nil(t, val)
+	assert.Nil(t, c.Scan(nil))
+	assert.Equal(t, c, Cpv{})
+
+	// invalid
+	assert.NotNil(t, c.Scan("=cat/pkg-1"))
+	assert.NotNil(t, c.Scan(1.0))
+}
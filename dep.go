@@ -1,3 +1,5 @@
+//go:build cgo && !nocgo
+
 package pkgcraft
 
 // #cgo pkg-config: pkgcraft
@@ -5,7 +7,9 @@ package pkgcraft
 import "C"
 
 import (
+	"fmt"
 	"runtime"
+	"unsafe"
 )
 
 type DependencyUnit int
@@ -16,6 +20,18 @@ const (
 	DependencyUnitUri
 )
 
+type DependencyKind int
+
+const (
+	DependencyEnabled DependencyKind = iota
+	DependencyDisabled
+	DependencyAllOf
+	DependencyAnyOf
+	DependencyExactlyOneOf
+	DependencyAtMostOneOf
+	DependencyConditional
+)
+
 type Dependency struct {
 	ptr *C.Dependency
 }
@@ -26,6 +42,62 @@ func depFromPtr(ptr *C.Dependency) *Dependency {
 	return obj
 }
 
+// Return a dependency's kind.
+func (self *Dependency) Kind() DependencyKind {
+	return DependencyKind(self.ptr.kind)
+}
+
+// Return the unit type of a dependency's values.
+func (self *Dependency) Unit() DependencyUnit {
+	return DependencyUnit(self.ptr.set)
+}
+
+// Return the number of direct children of a dependency.
+func (self *Dependency) Len() int {
+	return int(C.pkgcraft_dependency_len(self.ptr))
+}
+
+// Return the USE flag condition of a conditional dependency, e.g. "!flag?"
+// for the dependency "!flag? ( a/b )". An empty string is returned for all
+// other dependency kinds.
+func (self *Dependency) Conditional() string {
+	s := C.pkgcraft_dependency_conditional(self.ptr)
+	if s != nil {
+		defer C.pkgcraft_str_free(s)
+		return C.GoString(s)
+	}
+	return ""
+}
+
+// Return the direct children of a dependency.
+//
+// Enabled and disabled dependencies are leaf nodes and have no children, use
+// Value() to access their values instead.
+func (self *Dependency) Children() []*Dependency {
+	switch self.Kind() {
+	case DependencyEnabled, DependencyDisabled:
+		return nil
+	default:
+		return dependencyIterToSlice(C.pkgcraft_dependency_into_iter(self.ptr))
+	}
+}
+
+// Return the value of an enabled or disabled dependency.
+//
+// Depending on the dependency's unit this is a *Dep, string, or *Uri. For all
+// other dependency kinds nil is returned.
+func (self *Dependency) Value() interface{} {
+	switch self.Kind() {
+	case DependencyEnabled, DependencyDisabled:
+		iter := C.pkgcraft_dependency_into_iter_flatten(self.ptr)
+		defer C.pkgcraft_dependency_into_iter_flatten_free(iter)
+		if ptr := C.pkgcraft_dependency_into_iter_flatten_next(iter); ptr != nil {
+			return unitFromPtr(self.Unit(), ptr)
+		}
+	}
+	return nil
+}
+
 func (self *Dependency) String() string {
 	s := C.pkgcraft_dependency_str(self.ptr)
 	defer C.pkgcraft_str_free(s)
@@ -42,8 +114,193 @@ func dependencySetFromPtr(ptr *C.DependencySet) *DependencySet {
 	return obj
 }
 
+// Parse a string into a dependency set of the given unit type using a specific
+// EAPI, falling back to the latest EAPI if nil.
+func NewDependencySet(s string, unit DependencyUnit, eapi *Eapi) (*DependencySet, error) {
+	var eapi_ptr *C.Eapi
+	if eapi != nil {
+		eapi_ptr = eapi.ptr
+	}
+
+	c_str := C.CString(s)
+	defer C.free(unsafe.Pointer(c_str))
+	ptr := C.pkgcraft_dependency_set_parse(c_str, eapi_ptr, C.DependencySetKind(unit))
+	if ptr != nil {
+		return dependencySetFromPtr(ptr), nil
+	} else {
+		return nil, newPkgcraftError()
+	}
+}
+
+// Return the unit type of a dependency set's values.
+func (self *DependencySet) Unit() DependencyUnit {
+	return DependencyUnit(self.ptr.set)
+}
+
+// Return the number of top-level dependencies in a dependency set.
+func (self *DependencySet) Len() int {
+	return int(C.pkgcraft_dependency_set_len(self.ptr))
+}
+
+// Return the top-level dependencies of a dependency set.
+func (self *DependencySet) Dependencies() []*Dependency {
+	return dependencyIterToSlice(C.pkgcraft_dependency_set_into_iter(self.ptr))
+}
+
+// Return all the Dep objects in a dependency set, ignoring groups and
+// conditionals. Nil is returned for non-Dep unit sets.
+func (self *DependencySet) Deps() []*Dep {
+	var deps []*Dep
+	if self.Unit() == DependencyUnitDep {
+		for _, val := range self.flatten() {
+			deps = append(deps, val.(*Dep))
+		}
+	}
+	return deps
+}
+
+// Return all the strings in a dependency set, ignoring groups and
+// conditionals. Nil is returned for non-string unit sets.
+func (self *DependencySet) Strings() []string {
+	var vals []string
+	if self.Unit() == DependencyUnitString {
+		for _, val := range self.flatten() {
+			vals = append(vals, val.(string))
+		}
+	}
+	return vals
+}
+
+// Return all the Uri objects in a dependency set, ignoring groups and
+// conditionals. Nil is returned for non-URI unit sets.
+func (self *DependencySet) Uris() []*Uri {
+	var uris []*Uri
+	if self.Unit() == DependencyUnitUri {
+		for _, val := range self.flatten() {
+			uris = append(uris, val.(*Uri))
+		}
+	}
+	return uris
+}
+
+// Return the flattened values of a dependency set.
+func (self *DependencySet) flatten() []interface{} {
+	iter := C.pkgcraft_dependency_set_into_iter_flatten(self.ptr)
+	defer C.pkgcraft_dependency_into_iter_flatten_free(iter)
+	unit := self.Unit()
+	var vals []interface{}
+	for ptr := C.pkgcraft_dependency_into_iter_flatten_next(iter); ptr != nil; ptr = C.pkgcraft_dependency_into_iter_flatten_next(iter) {
+		vals = append(vals, unitFromPtr(unit, ptr))
+	}
+	return vals
+}
+
+// Evaluate the USE conditionals of a dependency set using the given enabled USE
+// flags, returning a new dependency set.
+//
+// Conditionals are replaced by their children when their conditions are
+// satisfied and dropped otherwise. All other groups, e.g. any-of, are retained.
+func (self *DependencySet) Evaluate(options []string) *DependencySet {
+	c_options, c_len := sliceToCharArray(options)
+	defer freeCharArray(c_options, c_len)
+	ptr := C.pkgcraft_dependency_set_evaluate(self.ptr, c_options, c_len)
+	return dependencySetFromPtr(ptr)
+}
+
 func (self *DependencySet) String() string {
 	s := C.pkgcraft_dependency_set_str(self.ptr)
 	defer C.pkgcraft_str_free(s)
 	return C.GoString(s)
 }
+
+func (self *DependencySet) Hash() uint64 {
+	return uint64(C.pkgcraft_dependency_set_hash(self.ptr))
+}
+
+// Determine if two dependency sets are equal, ignoring ordering.
+func (self *DependencySet) Equal(other *DependencySet) bool {
+	return bool(C.pkgcraft_dependency_set_eq(self.ptr, other.ptr))
+}
+
+// Return true if a dependency set contains a given object, false otherwise.
+//
+// Supported objects are *Dependency, *Dep, *Uri, and string values. Note that
+// only top-level dependencies are checked for Dependency objects while Dep,
+// Uri, and string values are checked against all the set's values.
+func (self *DependencySet) Contains(obj interface{}) bool {
+	switch obj := obj.(type) {
+	case *Dependency:
+		return bool(C.pkgcraft_dependency_set_contains_dependency(self.ptr, obj.ptr))
+	case *Dep:
+		return bool(C.pkgcraft_dependency_set_contains_dep(self.ptr, obj.ptr))
+	case *Uri:
+		return bool(C.pkgcraft_dependency_set_contains_uri(self.ptr, obj.ptr))
+	case string:
+		c_str := C.CString(obj)
+		defer C.free(unsafe.Pointer(c_str))
+		return bool(C.pkgcraft_dependency_set_contains_str(self.ptr, c_str))
+	default:
+		return false
+	}
+}
+
+// Perform a set operation on two dependency sets, returning a new set.
+func (self *DependencySet) opSet(op C.SetOp, other *DependencySet) (*DependencySet, error) {
+	if self.Unit() != other.Unit() {
+		return nil, fmt.Errorf("dependency set unit mismatch: %d != %d", self.Unit(), other.Unit())
+	}
+	ptr := C.pkgcraft_dependency_set_op_set(op, self.ptr, other.ptr)
+	if ptr != nil {
+		return dependencySetFromPtr(ptr), nil
+	} else {
+		return nil, newPkgcraftError()
+	}
+}
+
+// Return a new dependency set containing the dependencies of both sets.
+func (self *DependencySet) Union(other *DependencySet) (*DependencySet, error) {
+	return self.opSet(C.SET_OP_OR, other)
+}
+
+// Return a new dependency set containing the dependencies common to both sets.
+func (self *DependencySet) Intersection(other *DependencySet) (*DependencySet, error) {
+	return self.opSet(C.SET_OP_AND, other)
+}
+
+// Return a new dependency set containing the dependencies of the first set that
+// aren't in the second.
+func (self *DependencySet) Difference(other *DependencySet) (*DependencySet, error) {
+	return self.opSet(C.SET_OP_SUB, other)
+}
+
+// Return a new dependency set containing the dependencies in exactly one of
+// the sets.
+func (self *DependencySet) SymmetricDifference(other *DependencySet) (*DependencySet, error) {
+	return self.opSet(C.SET_OP_XOR, other)
+}
+
+// Consume a dependency iterator, converting it to a slice of Dependency objects.
+func dependencyIterToSlice(iter *C.DependencyIntoIter) []*Dependency {
+	defer C.pkgcraft_dependency_into_iter_free(iter)
+	var deps []*Dependency
+	for ptr := C.pkgcraft_dependency_into_iter_next(iter); ptr != nil; ptr = C.pkgcraft_dependency_into_iter_next(iter) {
+		deps = append(deps, depFromPtr(ptr))
+	}
+	return deps
+}
+
+// Convert a flattened dependency value pointer into its related Go type.
+func unitFromPtr(unit DependencyUnit, ptr unsafe.Pointer) interface{} {
+	switch unit {
+	case DependencyUnitDep:
+		return depPkgFromPtr((*C.Dep)(ptr))
+	case DependencyUnitString:
+		s := (*C.char)(ptr)
+		defer C.pkgcraft_str_free(s)
+		return C.GoString(s)
+	case DependencyUnitUri:
+		return uriFromPtr((*C.Uri)(ptr))
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,137 @@
+package pkgcraft
+
+import (
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+type DepField int
+
+const (
+	DepFieldCategory DepField = iota + 1
+	DepFieldPackage
+	DepFieldBlocker
+	DepFieldVersion
+	DepFieldSlot
+	DepFieldSubslot
+	DepFieldSlotOp
+	DepFieldUseDeps
+	DepFieldRepo
+)
+
+// Builder used to create modified Dep objects.
+type DepBuilder struct {
+	dep    *Dep
+	fields []DepField
+	values map[DepField]*string
+}
+
+// Return a builder for creating modified variants of a package dependency.
+func (self *Dep) Modify() *DepBuilder {
+	return &DepBuilder{dep: self, values: make(map[DepField]*string)}
+}
+
+// Set a field to a given value, nil values unset the field.
+func (self *DepBuilder) set(field DepField, value *string) *DepBuilder {
+	if _, exists := self.values[field]; !exists {
+		self.fields = append(self.fields, field)
+	}
+	self.values[field] = value
+	return self
+}
+
+// Set a field to a given string value, empty values unset the field.
+func (self *DepBuilder) setStr(field DepField, s string) *DepBuilder {
+	if s == "" {
+		return self.set(field, nil)
+	}
+	return self.set(field, &s)
+}
+
+// Set the blocker, BlockerNone removes it.
+func (self *DepBuilder) Blocker(blocker Blocker) *DepBuilder {
+	switch blocker {
+	case BlockerStrong:
+		return self.setStr(DepFieldBlocker, "!!")
+	case BlockerWeak:
+		return self.setStr(DepFieldBlocker, "!")
+	default:
+		return self.set(DepFieldBlocker, nil)
+	}
+}
+
+// Set the category.
+func (self *DepBuilder) Category(s string) *DepBuilder {
+	return self.setStr(DepFieldCategory, s)
+}
+
+// Set the package name.
+func (self *DepBuilder) Package(s string) *DepBuilder {
+	return self.setStr(DepFieldPackage, s)
+}
+
+// Set the version including its operator, e.g. ">=1.2-r3", an empty string
+// removes it.
+func (self *DepBuilder) Version(s string) *DepBuilder {
+	return self.setStr(DepFieldVersion, s)
+}
+
+// Set the slot, an empty string removes it.
+func (self *DepBuilder) Slot(s string) *DepBuilder {
+	return self.setStr(DepFieldSlot, s)
+}
+
+// Set the subslot, an empty string removes it.
+func (self *DepBuilder) Subslot(s string) *DepBuilder {
+	return self.setStr(DepFieldSubslot, s)
+}
+
+// Set the slot operator, SlotOpNone removes it.
+func (self *DepBuilder) SlotOp(op SlotOperator) *DepBuilder {
+	switch op {
+	case SlotOpEqual:
+		return self.setStr(DepFieldSlotOp, "=")
+	case SlotOpStar:
+		return self.setStr(DepFieldSlotOp, "*")
+	default:
+		return self.set(DepFieldSlotOp, nil)
+	}
+}
+
+// Replace the USE dependencies, an empty slice removes them.
+func (self *DepBuilder) Use(use []string) *DepBuilder {
+	return self.setStr(DepFieldUseDeps, strings.Join(use, ","))
+}
+
+// Add USE dependencies to the existing ones, skipping duplicates.
+func (self *DepBuilder) AddUse(use ...string) *DepBuilder {
+	var existing []string
+	if value, exists := self.values[DepFieldUseDeps]; exists {
+		if value != nil {
+			existing = strings.Split(*value, ",")
+		}
+	} else {
+		existing = self.dep.Use()
+	}
+
+	for _, s := range use {
+		if !slices.Contains(existing, s) {
+			existing = append(existing, s)
+		}
+	}
+	return self.Use(existing)
+}
+
+// Set the repo, an empty string removes it.
+func (self *DepBuilder) Repo(s string) *DepBuilder {
+	return self.setStr(DepFieldRepo, s)
+}
+
+// Remove the given fields.
+func (self *DepBuilder) Unset(fields ...DepField) *DepBuilder {
+	for _, field := range fields {
+		self.set(field, nil)
+	}
+	return self
+}
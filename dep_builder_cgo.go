@@ -0,0 +1,40 @@
+//go:build cgo && !nocgo
+
+package pkgcraft
+
+// #cgo pkg-config: pkgcraft
+// #include <pkgcraft.h>
+import "C"
+
+import (
+	"unsafe"
+)
+
+// Create a new package dependency from the original with all modifications
+// applied, returning an error if the result is invalid.
+func (self *DepBuilder) Build() (*Dep, error) {
+	length := len(self.fields)
+	c_fields := make([]C.DepField, length+1)
+	c_values := (**C.char)(C.calloc(C.size_t(length+1), C.size_t(unsafe.Sizeof(uintptr(0)))))
+	values := unsafe.Slice(c_values, length+1)
+	defer func() {
+		for _, s := range values {
+			C.free(unsafe.Pointer(s))
+		}
+		C.free(unsafe.Pointer(c_values))
+	}()
+
+	for i, field := range self.fields {
+		c_fields[i] = C.DepField(field)
+		if value := self.values[field]; value != nil {
+			values[i] = C.CString(*value)
+		}
+	}
+
+	ptr := C.pkgcraft_dep_modify(self.dep.ptr, &c_fields[0], c_values, C.size_t(length))
+	if ptr != nil {
+		return depPkgFromPtr(ptr), nil
+	} else {
+		return nil, newPkgcraftError()
+	}
+}
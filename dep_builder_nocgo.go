@@ -0,0 +1,93 @@
+//go:build !cgo || nocgo
+
+package pkgcraft
+
+import (
+	"fmt"
+)
+
+// Create a new package dependency from the original with all modifications
+// applied, returning an error if the result is invalid.
+func (self *DepBuilder) Build() (*Dep, error) {
+	dep, err := self.build()
+	if err != nil {
+		return nil, newParseError("invalid dep modification: %s: %s", self.dep, err)
+	}
+	return dep, nil
+}
+
+func (self *DepBuilder) build() (*Dep, error) {
+	dep := *self.dep
+	dep._key = ""
+	eapi := dep.eapi
+	if eapi == nil {
+		eapi = EAPI_LATEST
+	}
+
+	for _, field := range self.fields {
+		value := self.values[field]
+		var s string
+		if value != nil {
+			s = *value
+		}
+
+		var err error
+		switch field {
+		case DepFieldCategory:
+			if !validCategory(s) {
+				return nil, fmt.Errorf("invalid category: %s", s)
+			}
+			dep.category = s
+		case DepFieldPackage:
+			if !validPackage(s) {
+				return nil, fmt.Errorf("invalid package: %s", s)
+			}
+			dep.pkg = s
+		case DepFieldBlocker:
+			if value == nil {
+				dep.blocker = BlockerNone
+			} else if dep.blocker, err = BlockerFromString(s); err != nil {
+				return nil, err
+			}
+		case DepFieldVersion:
+			if value == nil {
+				dep.version = nil
+			} else if dep.version, err = NewVersionWithOp(s); err != nil {
+				return nil, err
+			}
+		case DepFieldSlot:
+			if value != nil && !slotRe.MatchString(s) {
+				return nil, fmt.Errorf("invalid slot: %s", s)
+			}
+			dep.slot = s
+		case DepFieldSubslot:
+			if value != nil && !slotRe.MatchString(s) {
+				return nil, fmt.Errorf("invalid subslot: %s", s)
+			}
+			dep.subslot = s
+		case DepFieldSlotOp:
+			if value == nil {
+				dep.slotOp = SlotOpNone
+			} else if dep.slotOp, err = SlotOperatorFromString(s); err != nil {
+				return nil, err
+			}
+		case DepFieldUseDeps:
+			if value == nil {
+				dep.use = nil
+			} else if dep.use, err = parseUseDeps(s); err != nil {
+				return nil, err
+			}
+		case DepFieldRepo:
+			if value == nil {
+				dep.repo = ""
+			} else if dep.repo, err = parseRepoDep(s, eapi); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if dep.subslot != "" && dep.slot == "" {
+		return nil, fmt.Errorf("subslot without slot: %s", dep.subslot)
+	}
+	return &dep, nil
+}
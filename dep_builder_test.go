@@ -0,0 +1,68 @@
+package pkgcraft_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/pkgcraft/pkgcraft-go"
+)
+
+func TestDepModify(t *testing.T) {
+	var dep *Dep
+	var err error
+	orig, _ := NewDep("=cat/pkg-1-r2:3/4=::repo[a,b]")
+
+	// no modifications
+	dep, err = orig.Modify().Build()
+	assert.Nil(t, err)
+	assert.Equal(t, dep.Cmp(orig), 0)
+	assert.True(t, dep != orig)
+
+	// set fields
+	dep, err = orig.Modify().Blocker(BlockerStrong).Version(">=2").Slot("5").Subslot("6").SlotOp(SlotOpStar).Repo("gentoo").Build()
+	assert.Nil(t, err)
+	assert.Equal(t, dep.String(), "!!>=cat/pkg-2:5/6*::gentoo[a,b]")
+
+	// unset fields
+	dep, err = orig.Modify().Version("").Slot("").Subslot("").SlotOp(SlotOpNone).Use(nil).Repo("").Build()
+	assert.Nil(t, err)
+	assert.Equal(t, dep.String(), "cat/pkg")
+	dep, err = orig.Modify().Unset(DepFieldVersion, DepFieldRepo).Build()
+	assert.Nil(t, err)
+	assert.Equal(t, dep.String(), "cat/pkg:3/4=[a,b]")
+
+	// category and package
+	dep, err = orig.Modify().Category("a").Package("b").Build()
+	assert.Nil(t, err)
+	assert.Equal(t, dep.String(), "=a/b-1-r2:3/4=::repo[a,b]")
+
+	// replace USE deps
+	dep, err = orig.Modify().Use([]string{"c", "-d"}).Build()
+	assert.Nil(t, err)
+	assert.Equal(t, dep.Use(), []string{"c", "-d"})
+
+	// add USE deps
+	dep, err = orig.Modify().AddUse("b", "c").Build()
+	assert.Nil(t, err)
+	assert.Equal(t, dep.Use(), []string{"a", "b", "c"})
+	dep, err = orig.Modify().Use(nil).AddUse("c").Build()
+	assert.Nil(t, err)
+	assert.Equal(t, dep.Use(), []string{"c"})
+
+	// later modifications override earlier ones
+	dep, err = orig.Modify().Slot("1").Slot("2").Build()
+	assert.Nil(t, err)
+	assert.Equal(t, dep.Slot(), "2")
+
+	// original is unmodified
+	assert.Equal(t, orig.String(), "=cat/pkg-1-r2:3/4=::repo[a,b]")
+
+	// invalid
+	_, err = orig.Modify().Version("1").Build()
+	assert.NotNil(t, err)
+	_, err = orig.Modify().Slot("").Build()
+	assert.NotNil(t, err)
+	_, err = orig.Modify().Use([]string{"+"}).Build()
+	assert.NotNil(t, err)
+}
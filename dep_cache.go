@@ -0,0 +1,128 @@
+package pkgcraft
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/hashicorp/golang-lru/v2"
+)
+
+// Default maximum number of entries in the Dep cache.
+const DEP_CACHE_SIZE = 10000
+
+// Cache used by NewDepCached() and NewDepCachedWithEapi().
+//
+// Implementations must be safe for concurrent use. Note that the LRU caches
+// provided by github.com/hashicorp/golang-lru/v2 satisfy this interface.
+//
+// Cached Deps are shared by all callers and must be treated as immutable, e.g.
+// they must not be used as targets for unmarshalling or scanning since those
+// modify Deps in place.
+type DepCache interface {
+	// Return the cached Dep for a key if one exists.
+	Get(key Pair[string, *Eapi]) (*Dep, bool)
+	// Add a Dep to the cache, returning true if an entry was evicted.
+	Add(key Pair[string, *Eapi], dep *Dep) bool
+	// Remove all entries from the cache.
+	Purge()
+	// Return the number of entries in the cache.
+	Len() int
+}
+
+// Dep cache statistics.
+type DepCacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Len       int
+}
+
+var dep_cache_lock sync.RWMutex
+var dep_cache DepCache = newDepLru(DEP_CACHE_SIZE)
+var dep_cache_hits, dep_cache_misses, dep_cache_evictions atomic.Uint64
+
+func newDepLru(size int) DepCache {
+	cache, _ := lru.New[Pair[string, *Eapi], *Dep](size)
+	return cache
+}
+
+// Return the currently used Dep cache.
+func getDepCache() DepCache {
+	dep_cache_lock.RLock()
+	defer dep_cache_lock.RUnlock()
+	return dep_cache
+}
+
+// Replace the Dep cache, a nil value disables caching.
+//
+// Nil LRU caches are treated as nil values while other typed nil values, e.g.
+// nil pointers to custom implementations, are unsupported.
+func SetDepCache(cache DepCache) {
+	if c, ok := cache.(*lru.Cache[Pair[string, *Eapi], *Dep]); ok && c == nil {
+		cache = nil
+	}
+	dep_cache_lock.Lock()
+	defer dep_cache_lock.Unlock()
+	dep_cache = cache
+}
+
+// Replace the Dep cache with an LRU cache of the given size, a size of zero
+// disables caching.
+func SetDepCacheSize(size int) error {
+	if size < 0 {
+		return fmt.Errorf("invalid cache size: %d", size)
+	} else if size == 0 {
+		SetDepCache(nil)
+	} else {
+		SetDepCache(newDepLru(size))
+	}
+	return nil
+}
+
+// Remove all entries from the Dep cache.
+func PurgeDepCache() {
+	if cache := getDepCache(); cache != nil {
+		cache.Purge()
+	}
+}
+
+// Return the Dep cache's statistics.
+func GetDepCacheStats() DepCacheStats {
+	stats := DepCacheStats{
+		Hits:      dep_cache_hits.Load(),
+		Misses:    dep_cache_misses.Load(),
+		Evictions: dep_cache_evictions.Load(),
+	}
+	if cache := getDepCache(); cache != nil {
+		stats.Len = cache.Len()
+	}
+	return stats
+}
+
+// Reset the Dep cache's hit, miss, and eviction counters.
+func ResetDepCacheStats() {
+	dep_cache_hits.Store(0)
+	dep_cache_misses.Store(0)
+	dep_cache_evictions.Store(0)
+}
+
+func newCachedDep(s string, eapi *Eapi) (*Dep, error) {
+	cache := getDepCache()
+	if cache == nil {
+		return newDep(s, eapi)
+	}
+
+	key := Pair[string, *Eapi]{s, eapi}
+	if dep, ok := cache.Get(key); ok {
+		dep_cache_hits.Add(1)
+		return dep, nil
+	} else {
+		dep_cache_misses.Add(1)
+		dep, err := newDep(s, eapi)
+		if err == nil && cache.Add(key, dep) {
+			dep_cache_evictions.Add(1)
+		}
+		return dep, err
+	}
+}
@@ -0,0 +1,85 @@
+package pkgcraft_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/golang-lru/v2"
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/pkgcraft/pkgcraft-go"
+)
+
+func TestDepCacheStats(t *testing.T) {
+	assert.Nil(t, SetDepCacheSize(2))
+	defer SetDepCacheSize(DEP_CACHE_SIZE)
+	ResetDepCacheStats()
+	assert.Equal(t, GetDepCacheStats(), DepCacheStats{})
+
+	// misses
+	d1, _ := NewDepCached("cat/pkg")
+	NewDepCached("cat/pkg::repo")
+	assert.Equal(t, GetDepCacheStats(), DepCacheStats{Misses: 2, Len: 2})
+
+	// hits
+	d2, _ := NewDepCached("cat/pkg")
+	assert.True(t, d1 == d2)
+	assert.Equal(t, GetDepCacheStats(), DepCacheStats{Hits: 1, Misses: 2, Len: 2})
+
+	// evictions
+	NewDepCached("a/b")
+	assert.Equal(t, GetDepCacheStats(), DepCacheStats{Hits: 1, Misses: 3, Evictions: 1, Len: 2})
+
+	// invalid deps aren't cached
+	_, err := NewDepCached("cat/pkg-1")
+	assert.NotNil(t, err)
+	assert.Equal(t, GetDepCacheStats(), DepCacheStats{Hits: 1, Misses: 4, Evictions: 1, Len: 2})
+
+	// purging
+	PurgeDepCache()
+	assert.Equal(t, GetDepCacheStats().Len, 0)
+	d3, _ := NewDepCached("cat/pkg")
+	assert.True(t, d1 != d3)
+
+	// resetting
+	ResetDepCacheStats()
+	assert.Equal(t, GetDepCacheStats(), DepCacheStats{Len: 1})
+
+	// invalid size
+	assert.NotNil(t, SetDepCacheSize(-1))
+}
+
+func TestDepCacheDisabled(t *testing.T) {
+	assert.Nil(t, SetDepCacheSize(0))
+	defer SetDepCacheSize(DEP_CACHE_SIZE)
+	ResetDepCacheStats()
+
+	d1, _ := NewDepCached("cat/pkg")
+	d2, _ := NewDepCached("cat/pkg")
+	assert.True(t, d1 != d2)
+	assert.Equal(t, d1.Cmp(d2), 0)
+	assert.Equal(t, GetDepCacheStats(), DepCacheStats{})
+	PurgeDepCache()
+
+	// nil LRU caches disable caching
+	SetDepCache((*lru.Cache[Pair[string, *Eapi], *Dep])(nil))
+	d1, _ = NewDepCached("cat/pkg")
+	d2, _ = NewDepCached("cat/pkg")
+	assert.True(t, d1 != d2)
+	assert.Equal(t, GetDepCacheStats(), DepCacheStats{})
+	PurgeDepCache()
+}
+
+func TestDepCacheCustom(t *testing.T) {
+	cache, _ := lru.New[Pair[string, *Eapi], *Dep](100)
+	SetDepCache(cache)
+	defer SetDepCacheSize(DEP_CACHE_SIZE)
+
+	for i := 0; i < 10; i++ {
+		NewDepCached(fmt.Sprintf("=cat/pkg-%d", i))
+	}
+	assert.Equal(t, cache.Len(), 10)
+	dep, ok := cache.Get(Pair[string, *Eapi]{"=cat/pkg-0", nil})
+	assert.True(t, ok)
+	assert.Equal(t, dep.String(), "=cat/pkg-0")
+}
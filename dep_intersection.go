@@ -0,0 +1,155 @@
+package pkgcraft
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+// Error returned when intersecting package dependencies with glob versions.
+var ErrGlobUnsupported = errors.New("glob versions are unsupported")
+
+// Combined restrictions of two intersecting package dependencies.
+type DepIntersection struct {
+	Cpn      *Cpn
+	Versions *VersionRange
+	Slot     string
+	Subslot  string
+	SlotOp   SlotOperator
+	Use      []string
+	Repo     string
+}
+
+// Merge two optional string fields, failing if both are set to different values.
+func mergeDepField(name, a, b string) (string, error) {
+	switch {
+	case a == "":
+		return b, nil
+	case b == "" || a == b:
+		return a, nil
+	default:
+		return "", fmt.Errorf("%s mismatch: %s != %s", name, a, b)
+	}
+}
+
+// Merge USE dependencies, failing if a flag is required to be both enabled and
+// disabled.
+func mergeUseDeps(a, b *Dep) ([]string, error) {
+	use := a.Use()
+	for _, s := range b.Use() {
+		if !slices.Contains(use, s) {
+			use = append(use, s)
+		}
+	}
+
+	kinds := make(map[string]UseDepKind)
+	for _, s := range use {
+		u, _ := NewUseDep(s)
+		switch u.Kind() {
+		case UseDepEnabled, UseDepDisabled:
+			if kind, exists := kinds[u.Flag()]; exists && kind != u.Kind() {
+				return nil, fmt.Errorf("USE flag conflict: %s", u.Flag())
+			}
+			kinds[u.Flag()] = u.Kind()
+		}
+	}
+	return use, nil
+}
+
+// Return the combined restrictions of two package dependencies, returning an
+// error if they don't intersect.
+//
+// Versions are combined into the narrowest range matching both dependencies,
+// USE dependencies are merged, and slots, subslots, and repos must be equal if
+// specified by both. Slot operators are retained with "=" taking precedence
+// over "*" if they differ. Blockers are unsupported and glob versions result
+// in an error wrapping ErrGlobUnsupported.
+func (self *Dep) Intersection(other *Dep) (*DepIntersection, error) {
+	var err error
+	result := &DepIntersection{Cpn: self.Cpn()}
+	if self.Cpn().Cmp(other.Cpn()) != 0 {
+		return nil, fmt.Errorf("Cpn mismatch: %s != %s", self.Cpn(), other.Cpn())
+	}
+	for _, dep := range []*Dep{self, other} {
+		if dep.Blocker() != BlockerNone {
+			return nil, fmt.Errorf("unsupported intersection blocker: %s", dep)
+		} else if ver := dep.Version(); ver != nil && ver.Op() == OperatorEqualGlob {
+			return nil, fmt.Errorf("%w: %s", ErrGlobUnsupported, dep)
+		}
+	}
+
+	if result.Versions, err = NewVersionRange(self, other); err != nil {
+		return nil, err
+	} else if result.Versions.Empty() {
+		return nil, fmt.Errorf("version mismatch: %s != %s", self, other)
+	}
+
+	if result.Slot, err = mergeDepField("slot", self.Slot(), other.Slot()); err != nil {
+		return nil, err
+	}
+	if result.Subslot, err = mergeDepField("subslot", self.Subslot(), other.Subslot()); err != nil {
+		return nil, err
+	}
+	if result.Repo, err = mergeDepField("repo", self.Repo(), other.Repo()); err != nil {
+		return nil, err
+	}
+	if result.Use, err = mergeUseDeps(self, other); err != nil {
+		return nil, err
+	}
+
+	result.SlotOp = self.SlotOp()
+	if result.SlotOp == SlotOpNone || other.SlotOp() == SlotOpEqual {
+		result.SlotOp = other.SlotOp()
+	}
+
+	return result, nil
+}
+
+// Return the package dependencies that must all match in order to match the
+// intersection, e.g. [>=cat/pkg-1:2, <cat/pkg-2:2].
+func (self *DepIntersection) Deps() ([]*Dep, error) {
+	sets, err := self.Versions.Deps(self.Cpn)
+	if err != nil {
+		return nil, err
+	}
+	var deps []*Dep
+	for _, set := range sets {
+		for _, dep := range set {
+			builder := dep.Modify().Slot(self.Slot).Subslot(self.Subslot)
+			builder = builder.SlotOp(self.SlotOp).Use(self.Use).Repo(self.Repo)
+			dep, err := builder.Build()
+			if err != nil {
+				return nil, err
+			}
+			deps = append(deps, dep)
+		}
+	}
+	return deps, nil
+}
+
+// Return the intersection as a single package dependency, returning an error
+// if it isn't expressible as one, e.g. for bounded version ranges such as
+// ">=1,<2" use Deps() instead.
+func (self *DepIntersection) Dep() (*Dep, error) {
+	deps, err := self.Deps()
+	if err != nil {
+		return nil, err
+	} else if len(deps) != 1 {
+		return nil, fmt.Errorf("intersection not expressible as a single dep: %s", self)
+	}
+	return deps[0], nil
+}
+
+func (self *DepIntersection) String() string {
+	deps, err := self.Deps()
+	if err != nil {
+		return fmt.Sprintf("%s (%s)", self.Cpn, self.Versions)
+	}
+	var vals []string
+	for _, dep := range deps {
+		vals = append(vals, dep.String())
+	}
+	return strings.Join(vals, " ")
+}
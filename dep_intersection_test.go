@@ -0,0 +1,108 @@
+package pkgcraft_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/pkgcraft/pkgcraft-go"
+)
+
+func TestDepIntersection(t *testing.T) {
+	var d1, d2, dep *Dep
+	var deps []*Dep
+	var i *DepIntersection
+	var err error
+
+	// expressible as a single dep
+	d1, _ = NewDep(">=cat/pkg-1:2[a]")
+	d2, _ = NewDep(">=cat/pkg-1.5[b,-c]")
+	i, err = d1.Intersection(d2)
+	assert.Nil(t, err)
+	assert.Equal(t, i.Slot, "2")
+	assert.Equal(t, i.Use, []string{"a", "b", "-c"})
+	assert.Equal(t, i.Versions.String(), ">=1.5")
+	dep, err = i.Dep()
+	assert.Nil(t, err)
+	assert.Equal(t, dep.String(), ">=cat/pkg-1.5:2[a,b,-c]")
+
+	// unversioned deps
+	d1, _ = NewDep("cat/pkg:2/3=")
+	d2, _ = NewDep("cat/pkg::repo")
+	i, _ = d1.Intersection(d2)
+	dep, _ = i.Dep()
+	assert.Equal(t, dep.String(), "cat/pkg:2/3=::repo")
+
+	// exact versions
+	d1, _ = NewDep("~cat/pkg-1.2")
+	d2, _ = NewDep("<=cat/pkg-1.2-r1")
+	i, _ = d1.Intersection(d2)
+	deps, err = i.Deps()
+	assert.Nil(t, err)
+	assert.Equal(t, len(deps), 2)
+	d1, _ = NewDep("~cat/pkg-1.2")
+	d2, _ = NewDep("<=cat/pkg-1.2")
+	i, _ = d1.Intersection(d2)
+	dep, _ = i.Dep()
+	assert.Equal(t, dep.String(), "=cat/pkg-1.2")
+
+	// slot operators
+	d1, _ = NewDep("cat/pkg:*")
+	d2, _ = NewDep("cat/pkg:=")
+	i, _ = d1.Intersection(d2)
+	assert.Equal(t, i.SlotOp, SlotOpEqual)
+
+	// bounded ranges require multiple deps
+	d1, _ = NewDep(">=cat/pkg-1[a]")
+	d2, _ = NewDep("<cat/pkg-2")
+	i, err = d1.Intersection(d2)
+	assert.Nil(t, err)
+	_, err = i.Dep()
+	assert.NotNil(t, err)
+	deps, err = i.Deps()
+	assert.Nil(t, err)
+	assert.Equal(t, deps[0].String(), ">=cat/pkg-1[a]")
+	assert.Equal(t, deps[1].String(), "<cat/pkg-2[a]")
+	assert.Equal(t, i.String(), ">=cat/pkg-1[a] <cat/pkg-2[a]")
+
+	// non-intersecting
+	for _, vals := range [][]string{
+		{"a/b", "a/c"},
+		{">=a/b-2", "<a/b-1"},
+		{"a/b:1", "a/b:2"},
+		{"a/b:1/2", "a/b:1/3"},
+		{"a/b::r1", "a/b::r2"},
+		{"a/b[a]", "a/b[-a]"},
+	} {
+		d1, _ = NewDep(vals[0])
+		d2, _ = NewDep(vals[1])
+		_, err = d1.Intersection(d2)
+		assert.NotNil(t, err, "%s and %s intersect", d1, d2)
+		assert.NotErrorIs(t, err, ErrGlobUnsupported)
+	}
+
+	// glob versions
+	for _, vals := range [][]string{
+		{"=a/b-1*", "a/b"},
+		{">=a/b-1", "=a/b-1.2*"},
+	} {
+		d1, _ = NewDep(vals[0])
+		d2, _ = NewDep(vals[1])
+		_, err = d1.Intersection(d2)
+		assert.ErrorIs(t, err, ErrGlobUnsupported, "%s and %s didn't fail", d1, d2)
+	}
+
+	// blockers
+	for _, vals := range [][]string{
+		{"!<a/b-2", "!<a/b-2"},
+		{"!!a/b", "!!a/b"},
+		{"!a/b", "a/b"},
+		{"a/b", "!<a/b-1"},
+	} {
+		d1, _ = NewDep(vals[0])
+		d2, _ = NewDep(vals[1])
+		_, err = d1.Intersection(d2)
+		assert.NotNil(t, err, "%s and %s didn't fail", d1, d2)
+		assert.NotErrorIs(t, err, ErrGlobUnsupported)
+	}
+}
@@ -1,3 +1,5 @@
+//go:build cgo && !nocgo
+
 package pkgcraft
 
 // #cgo pkg-config: pkgcraft
@@ -5,30 +7,25 @@ package pkgcraft
 import "C"
 
 import (
+	"database/sql/driver"
+	"encoding/json"
 	"fmt"
 	"runtime"
 	"unsafe"
-
-	"github.com/hashicorp/golang-lru/v2"
 )
 
 type Dep struct {
-	ptr *C.Dep
+	ptr  *C.Dep
+	eapi *Eapi
 	// cached fields
 	_category string
 	_package  string
 	_version  *Version
 	_hash     uint64
+	// owner of the C pointer for objects populated in place
+	_owner *Dep
 }
 
-type Blocker int
-
-const (
-	BlockerNone Blocker = iota
-	BlockerStrong
-	BlockerWeak
-)
-
 func BlockerFromString(s string) (Blocker, error) {
 	c_str := C.CString(s)
 	i := C.pkgcraft_dep_blocker_from_str(c_str)
@@ -40,14 +37,6 @@ func BlockerFromString(s string) (Blocker, error) {
 	}
 }
 
-type SlotOperator int
-
-const (
-	SlotOpNone SlotOperator = iota
-	SlotOpEqual
-	SlotOpStar
-)
-
 func SlotOperatorFromString(s string) (SlotOperator, error) {
 	c_str := C.CString(s)
 	i := C.pkgcraft_dep_slot_op_from_str(c_str)
@@ -59,13 +48,6 @@ func SlotOperatorFromString(s string) (SlotOperator, error) {
 	}
 }
 
-type Pair[T, U any] struct {
-	First  T
-	Second U
-}
-
-var dep_cache, _ = lru.New[Pair[string, *Eapi], *Dep](10000)
-
 func newDep(s string, eapi *Eapi) (*Dep, error) {
 	var eapi_ptr *C.Eapi
 	if eapi == nil {
@@ -79,14 +61,21 @@ func newDep(s string, eapi *Eapi) (*Dep, error) {
 	C.free(unsafe.Pointer(c_str))
 
 	if ptr != nil {
-		dep := &Dep{ptr: ptr}
-		runtime.SetFinalizer(dep, func(self *Dep) { C.pkgcraft_dep_free(self.ptr) })
+		dep := depPkgFromPtr(ptr)
+		dep.eapi = eapi
 		return dep, nil
 	} else {
 		return nil, newPkgcraftError()
 	}
 }
 
+// Return a new Dep from a given pointer.
+func depPkgFromPtr(ptr *C.Dep) *Dep {
+	dep := &Dep{ptr: ptr}
+	runtime.SetFinalizer(dep, func(*Dep) { C.pkgcraft_dep_free(ptr) })
+	return dep
+}
+
 // Parse a string into a Dep using the latest EAPI.
 func NewDep(s string) (*Dep, error) {
 	return newDep(s, nil)
@@ -97,19 +86,6 @@ func NewDepWithEapi(s string, eapi *Eapi) (*Dep, error) {
 	return newDep(s, eapi)
 }
 
-func newCachedDep(s string, eapi *Eapi) (*Dep, error) {
-	key := Pair[string, *Eapi]{s, eapi}
-	if dep, ok := dep_cache.Get(key); ok {
-		return dep, nil
-	} else {
-		dep, err := newDep(s, eapi)
-		if err == nil {
-			dep_cache.Add(key, dep)
-		}
-		return dep, err
-	}
-}
-
 // Return a cached Dep if one exists, otherwise return a new instance.
 func NewDepCached(s string) (*Dep, error) {
 	return newCachedDep(s, nil)
@@ -201,6 +177,17 @@ func (self *Dep) Use() []string {
 	return use
 }
 
+// Return a package dependency's USE flag dependencies as UseDep objects.
+func (self *Dep) UseDeps() []*UseDep {
+	var use_deps []*UseDep
+	for _, s := range self.Use() {
+		// USE dependencies are validated during Dep parsing
+		use_dep, _ := NewUseDep(s)
+		use_deps = append(use_deps, use_dep)
+	}
+	return use_deps
+}
+
 // Return a package dependency's repository.
 func (self *Dep) Repo() string {
 	s := C.pkgcraft_dep_repo(self.ptr)
@@ -238,6 +225,80 @@ func (self *Dep) Hash() uint64 {
 	return self._hash
 }
 
+func (self *Dep) MarshalText() ([]byte, error) {
+	if self == nil || self.ptr == nil {
+		return []byte{}, nil
+	}
+	return []byte(self.String()), nil
+}
+
+// Unmarshal a package dependency from text using the latest EAPI.
+func (self *Dep) UnmarshalText(data []byte) error {
+	return self.unmarshal(string(data), nil)
+}
+
+// JSON representation of a Dep parsed using a specific EAPI.
+type depJSON struct {
+	Dep  string `json:"dep"`
+	Eapi *Eapi  `json:"eapi"`
+}
+
+// Marshal a package dependency to JSON.
+//
+// Dependencies parsed using a specific EAPI are encoded as objects of the form
+// {"dep": "=cat/pkg-1", "eapi": "8"} while all others are encoded as strings.
+func (self *Dep) MarshalJSON() ([]byte, error) {
+	if self.eapi != nil {
+		return json.Marshal(depJSON{self.String(), self.eapi})
+	}
+	text, _ := self.MarshalText()
+	return json.Marshal(string(text))
+}
+
+// Unmarshal a package dependency from a JSON string or object, see MarshalJSON().
+func (self *Dep) UnmarshalJSON(data []byte) error {
+	var obj depJSON
+	if err := json.Unmarshal(data, &obj.Dep); err != nil {
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return err
+		}
+		if obj.Eapi != nil {
+			// use the global Eapi object to retain pointer equality
+			obj.Eapi = EAPIS[obj.Eapi.String()]
+		}
+	}
+	return self.unmarshal(obj.Dep, obj.Eapi)
+}
+
+// Parse a string into an existing package dependency.
+func (self *Dep) unmarshal(s string, eapi *Eapi) error {
+	dep, err := newDep(s, eapi)
+	if err != nil {
+		return err
+	}
+
+	populate(self, dep, &self._owner)
+	return nil
+}
+
+// Implement the sql.Scanner interface, parsing values using the latest EAPI.
+// NULL values are scanned as the zero value.
+func (self *Dep) Scan(src interface{}) error {
+	if src == nil {
+		*self = Dep{}
+		return nil
+	}
+	return scanText(self, src)
+}
+
+// Implement the driver.Valuer interface, the zero value is stored as NULL.
+func (self *Dep) Value() (driver.Value, error) {
+	if self == nil || self.ptr == nil {
+		return nil, nil
+	}
+	return self.String(), nil
+}
+
 // Compare two package dependencies returning -1, 0, or 1 if the first is
 // less than, equal to, or greater than the second, respectively.
 func (self *Dep) Cmp(other *Dep) int {
@@ -0,0 +1,514 @@
+//go:build !cgo || nocgo
+
+package pkgcraft
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+var slotRe = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9+_.-]*$`)
+var repoRe = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_-]*$`)
+
+type Dep struct {
+	eapi     *Eapi
+	blocker  Blocker
+	category string
+	pkg      string
+	version  *Version
+	slot     string
+	subslot  string
+	slotOp   SlotOperator
+	use      []string
+	repo     string
+	// cached fields
+	_key string
+}
+
+func BlockerFromString(s string) (Blocker, error) {
+	switch s {
+	case "!!":
+		return BlockerStrong, nil
+	case "!":
+		return BlockerWeak, nil
+	default:
+		return BlockerNone, fmt.Errorf("invalid blocker: %s", s)
+	}
+}
+
+func SlotOperatorFromString(s string) (SlotOperator, error) {
+	switch s {
+	case "=":
+		return SlotOpEqual, nil
+	case "*":
+		return SlotOpStar, nil
+	default:
+		return SlotOpNone, fmt.Errorf("invalid slot operator: %s", s)
+	}
+}
+
+func (self Blocker) prefix() string {
+	switch self {
+	case BlockerStrong:
+		return "!!"
+	case BlockerWeak:
+		return "!"
+	default:
+		return ""
+	}
+}
+
+func (self SlotOperator) suffix() string {
+	switch self {
+	case SlotOpEqual:
+		return "="
+	case SlotOpStar:
+		return "*"
+	default:
+		return ""
+	}
+}
+
+// Parse a slot dependency, e.g. "1/2=", into its components.
+func parseSlotDep(s string) (string, string, SlotOperator, error) {
+	var slot_op SlotOperator
+	switch {
+	case s == "*":
+		return "", "", SlotOpStar, nil
+	case strings.HasSuffix(s, "="):
+		s = strings.TrimSuffix(s, "=")
+		slot_op = SlotOpEqual
+		if s == "" {
+			return "", "", slot_op, nil
+		}
+	}
+
+	slot, subslot, found := strings.Cut(s, "/")
+	if !slotRe.MatchString(slot) || (found && !slotRe.MatchString(subslot)) {
+		return "", "", SlotOpNone, fmt.Errorf("invalid slot: %s", s)
+	}
+	return slot, subslot, slot_op, nil
+}
+
+// Parse USE dependencies, e.g. "a,-b,c?", sorting them by flag.
+func parseUseDeps(s string) ([]string, error) {
+	var use_deps []*UseDep
+	for _, u := range strings.Split(s, ",") {
+		use_dep, err := NewUseDep(u)
+		if err != nil {
+			return nil, err
+		}
+		use_deps = append(use_deps, use_dep)
+	}
+	slices.SortStableFunc(use_deps, func(a, b *UseDep) int { return strings.Compare(a.flag, b.flag) })
+
+	var use []string
+	for _, u := range use_deps {
+		use = append(use, u.String())
+	}
+	return use, nil
+}
+
+// Verify a repo dependency is valid for a given EAPI.
+func parseRepoDep(s string, eapi *Eapi) (string, error) {
+	if !eapi.Has("RepoIds") {
+		return "", fmt.Errorf("repo deps aren't supported in EAPI %s", eapi)
+	} else if !repoRe.MatchString(s) {
+		return "", fmt.Errorf("invalid repo: %s", s)
+	}
+	return s, nil
+}
+
+// Parse a package dependency string.
+func parseDep(s string, eapi *Eapi) (*Dep, error) {
+	dep := &Dep{eapi: eapi}
+	if eapi == nil {
+		eapi = EAPI_LATEST
+	}
+
+	var err error
+	rest := s
+	if i := strings.LastIndex(rest, "["); i >= 0 && strings.HasSuffix(rest, "]") {
+		if dep.use, err = parseUseDeps(rest[i+1 : len(rest)-1]); err != nil {
+			return nil, err
+		}
+		rest = rest[:i]
+	}
+	if i := strings.LastIndex(rest, "::"); i >= 0 {
+		if dep.repo, err = parseRepoDep(rest[i+2:], eapi); err != nil {
+			return nil, err
+		}
+		rest = rest[:i]
+	}
+	if i := strings.Index(rest, ":"); i >= 0 {
+		if dep.slot, dep.subslot, dep.slotOp, err = parseSlotDep(rest[i+1:]); err != nil {
+			return nil, err
+		}
+		rest = rest[:i]
+	}
+
+	if strings.HasPrefix(rest, "!!") {
+		dep.blocker = BlockerStrong
+	} else if strings.HasPrefix(rest, "!") {
+		dep.blocker = BlockerWeak
+	}
+	rest = rest[len(dep.blocker.prefix()):]
+
+	var op Operator
+	for _, prefix := range []string{"<=", ">=", "<", ">", "=", "~"} {
+		if strings.HasPrefix(rest, prefix) {
+			op, _ = OperatorFromString(prefix)
+			rest = rest[len(prefix):]
+			break
+		}
+	}
+
+	if op == OperatorNone {
+		cpn, err := NewCpn(rest)
+		if err != nil {
+			return nil, err
+		}
+		dep.category, dep.pkg = cpn.category, cpn.pkg
+	} else {
+		cpv, err := parseCpv(rest, op)
+		if err != nil {
+			return nil, err
+		}
+		dep.category, dep.pkg, dep.version = cpv.category, cpv.pkg, cpv.version
+	}
+
+	return dep, nil
+}
+
+func newDep(s string, eapi *Eapi) (*Dep, error) {
+	dep, err := parseDep(s, eapi)
+	if err != nil {
+		return nil, newParseError("invalid dep: %s: %s", s, err)
+	}
+	return dep, nil
+}
+
+// Parse a string into a Dep using the latest EAPI.
+func NewDep(s string) (*Dep, error) {
+	return newDep(s, nil)
+}
+
+// Parse a string into a Dep using a specific EAPI.
+func NewDepWithEapi(s string, eapi *Eapi) (*Dep, error) {
+	return newDep(s, eapi)
+}
+
+// Return a cached Dep if one exists, otherwise return a new instance.
+func NewDepCached(s string) (*Dep, error) {
+	return newCachedDep(s, nil)
+}
+
+// Return a cached Dep if one exists, otherwise parse using a specific EAPI.
+func NewDepCachedWithEapi(s string, eapi *Eapi) (*Dep, error) {
+	return newCachedDep(s, eapi)
+}
+
+// Get the blocker of a package dependency.
+func (self *Dep) Blocker() Blocker {
+	return self.blocker
+}
+
+// Return a package dependency's category.
+func (self *Dep) Category() string {
+	return self.category
+}
+
+// Return a package dependency's package.
+func (self *Dep) Package() string {
+	return self.pkg
+}
+
+// Return a package dependency's version.
+func (self *Dep) Version() *Version {
+	if self.version == nil {
+		return &Version{}
+	}
+	return self.version
+}
+
+// Return a package dependency's revision.
+func (self *Dep) Revision() *Revision {
+	return self.Version().Revision()
+}
+
+// Return a package dependency's slot.
+func (self *Dep) Slot() string {
+	return self.slot
+}
+
+// Return a package dependency's subslot.
+func (self *Dep) Subslot() string {
+	return self.subslot
+}
+
+// Return a package dependency's slot operator.
+func (self *Dep) SlotOp() SlotOperator {
+	return self.slotOp
+}
+
+// Return a package dependency's USE flag dependencies.
+func (self *Dep) Use() []string {
+	return slices.Clone(self.use)
+}
+
+// Return a package dependency's USE flag dependencies as UseDep objects.
+func (self *Dep) UseDeps() []*UseDep {
+	var use_deps []*UseDep
+	for _, s := range self.use {
+		// USE dependencies are validated during Dep parsing
+		use_dep, _ := NewUseDep(s)
+		use_deps = append(use_deps, use_dep)
+	}
+	return use_deps
+}
+
+// Return a package dependency's repository.
+func (self *Dep) Repo() string {
+	return self.repo
+}
+
+// Return a package dependency's Cpn.
+func (self *Dep) Cpn() *Cpn {
+	return &Cpn{self.category, self.pkg}
+}
+
+// Return the Cpv of a package dependency if one exists.
+func (self *Dep) Cpv() *Cpv {
+	if self.version != nil {
+		return &Cpv{category: self.category, pkg: self.pkg, version: self.version.withoutOp()}
+	}
+	return nil
+}
+
+func (self *Dep) String() string {
+	var b strings.Builder
+	b.WriteString(self.blocker.prefix())
+	if self.version != nil {
+		b.WriteString(strings.TrimSuffix(self.version.op.String(), "*"))
+	}
+	b.WriteString(self.category + "/" + self.pkg)
+	if self.version != nil {
+		b.WriteString("-" + self.version.unprefixed())
+	}
+	if self.slot != "" || self.slotOp != SlotOpNone {
+		b.WriteString(":" + self.slot)
+		if self.subslot != "" {
+			b.WriteString("/" + self.subslot)
+		}
+		b.WriteString(self.slotOp.suffix())
+	}
+	if self.repo != "" {
+		b.WriteString("::" + self.repo)
+	}
+	if self.use != nil {
+		b.WriteString("[" + strings.Join(self.use, ",") + "]")
+	}
+	return b.String()
+}
+
+// Return a string that is equal for equal package dependencies.
+func (self *Dep) key() string {
+	if self._key == "" {
+		fields := []string{
+			self.category,
+			self.pkg,
+			self.Version().sortKey(),
+			self.Version().op.String(),
+			self.blocker.prefix(),
+			self.slot,
+			self.subslot,
+			self.slotOp.suffix(),
+			strings.Join(self.use, ","),
+			self.repo,
+		}
+		self._key = strings.Join(fields, "\x00")
+	}
+	return self._key
+}
+
+func (self *Dep) Hash() uint64 {
+	return hashString(self.key())
+}
+
+func (self *Dep) MarshalText() ([]byte, error) {
+	if self == nil || self.category == "" {
+		return []byte{}, nil
+	}
+	return []byte(self.String()), nil
+}
+
+// Unmarshal a package dependency from text using the latest EAPI.
+func (self *Dep) UnmarshalText(data []byte) error {
+	return self.unmarshal(string(data), nil)
+}
+
+// JSON representation of a Dep parsed using a specific EAPI.
+type depJSON struct {
+	Dep  string `json:"dep"`
+	Eapi *Eapi  `json:"eapi"`
+}
+
+// Marshal a package dependency to JSON.
+//
+// Dependencies parsed using a specific EAPI are encoded as objects of the form
+// {"dep": "=cat/pkg-1", "eapi": "8"} while all others are encoded as strings.
+func (self *Dep) MarshalJSON() ([]byte, error) {
+	if self.eapi != nil {
+		return json.Marshal(depJSON{self.String(), self.eapi})
+	}
+	text, _ := self.MarshalText()
+	return json.Marshal(string(text))
+}
+
+// Unmarshal a package dependency from a JSON string or object, see MarshalJSON().
+func (self *Dep) UnmarshalJSON(data []byte) error {
+	var obj depJSON
+	if err := json.Unmarshal(data, &obj.Dep); err != nil {
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return err
+		}
+		if obj.Eapi != nil {
+			// use the global Eapi object to retain pointer equality
+			obj.Eapi = EAPIS[obj.Eapi.String()]
+		}
+	}
+	return self.unmarshal(obj.Dep, obj.Eapi)
+}
+
+// Parse a string into an existing package dependency.
+func (self *Dep) unmarshal(s string, eapi *Eapi) error {
+	dep, err := newDep(s, eapi)
+	if err != nil {
+		return err
+	}
+	*self = *dep
+	return nil
+}
+
+// Implement the sql.Scanner interface, parsing values using the latest EAPI.
+// NULL values are scanned as the zero value.
+func (self *Dep) Scan(src interface{}) error {
+	if src == nil {
+		*self = Dep{}
+		return nil
+	}
+	return scanText(self, src)
+}
+
+// Implement the driver.Valuer interface, the zero value is stored as NULL.
+func (self *Dep) Value() (driver.Value, error) {
+	if self == nil || self.category == "" {
+		return nil, nil
+	}
+	return self.String(), nil
+}
+
+// Compare two package dependencies returning -1, 0, or 1 if the first is
+// less than, equal to, or greater than the second, respectively.
+func (self *Dep) Cmp(other *Dep) int {
+	if c := self.Cpn().Cmp(other.Cpn()); c != 0 {
+		return c
+	}
+
+	// unversioned deps sort before versioned ones
+	switch {
+	case self.version == nil && other.version != nil:
+		return -1
+	case self.version != nil && other.version == nil:
+		return 1
+	case self.version != nil:
+		if c := self.version.Cmp(other.version); c != 0 {
+			return c
+		}
+	}
+
+	if c := cmpInts(int(self.blocker), int(other.blocker)); c != 0 {
+		return c
+	}
+	if c := cmpStrings(self.slot, other.slot); c != 0 {
+		return c
+	}
+	if c := cmpStrings(self.subslot, other.subslot); c != 0 {
+		return c
+	}
+	if c := cmpInts(int(self.slotOp), int(other.slotOp)); c != 0 {
+		return c
+	}
+	if c := slices.Compare(self.use, other.use); c != 0 {
+		return c
+	}
+	return cmpStrings(self.repo, other.repo)
+}
+
+// Return the enabled and disabled USE flags required by a package dependency,
+// ignoring conditional USE dependencies.
+func (self *Dep) useFlags() (map[string]bool, map[string]bool) {
+	enabled := make(map[string]bool)
+	disabled := make(map[string]bool)
+	for _, u := range self.UseDeps() {
+		switch u.Kind() {
+		case UseDepEnabled:
+			enabled[u.Flag()] = true
+		case UseDepDisabled:
+			disabled[u.Flag()] = true
+		}
+	}
+	return enabled, disabled
+}
+
+// Determine if two Cpv or Dep objects intersect.
+func (self *Dep) Intersects(other interface{}) bool {
+	switch other := other.(type) {
+	case *Cpv:
+		if self.category != other.category || self.pkg != other.pkg {
+			return false
+		}
+		return self.version == nil || self.version.contains(other.version)
+	case *Dep:
+		if self.category != other.category || self.pkg != other.pkg {
+			return false
+		}
+
+		// fields specified by both deps must match
+		for _, field := range [][2]string{
+			{self.slot, other.slot},
+			{self.subslot, other.subslot},
+			{self.repo, other.repo},
+		} {
+			if field[0] != "" && field[1] != "" && field[0] != field[1] {
+				return false
+			}
+		}
+
+		// USE flags can't be both enabled and disabled
+		enabled, disabled := self.useFlags()
+		other_enabled, other_disabled := other.useFlags()
+		for flag := range enabled {
+			if other_disabled[flag] {
+				return false
+			}
+		}
+		for flag := range disabled {
+			if other_enabled[flag] {
+				return false
+			}
+		}
+
+		if self.version != nil && other.version != nil {
+			return self.version.Intersects(other.version)
+		}
+		return true
+	default:
+		return false
+	}
+}
@@ -1,6 +1,7 @@
 package pkgcraft_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"sort"
 	"testing"
@@ -81,6 +82,14 @@ func TestDepAttrs(t *testing.T) {
 	assert.Equal(t, dep.Cpv(), cpv)
 	assert.Equal(t, dep.String(), "=cat/pkg-1-r2")
 
+	// version operator
+	dep, _ = NewDep(">=cat/pkg-1")
+	assert.Equal(t, dep.Version().Op(), OperatorGreaterOrEqual)
+	dep, _ = NewDep("<cat/pkg-1")
+	assert.Equal(t, dep.Version().Op(), OperatorLess)
+	dep, _ = NewDep("cat/pkg")
+	assert.Equal(t, dep.Version().Op(), OperatorNone)
+
 	// blocker
 	dep, err = NewDep("!cat/pkg")
 	assert.Nil(t, err)
@@ -332,3 +341,73 @@ func BenchmarkDepSort(b *testing.B) {
 	}
 	assert.Equal(b, deps[0].String(), "=cat/pkg-1")
 }
+
+func TestDepMarshal(t *testing.T) {
+	var d Dep
+
+	// text
+	dep, _ := NewDep("=cat/pkg-1-r2:3/4=::repo[a,b,c]")
+	data, err := dep.MarshalText()
+	assert.Nil(t, err)
+	assert.Equal(t, string(data), "=cat/pkg-1-r2:3/4=::repo[a,b,c]")
+	assert.Nil(t, d.UnmarshalText(data))
+	assert.Equal(t, d.Cmp(dep), 0)
+
+	// JSON using the latest EAPI
+	data, err = json.Marshal(dep)
+	assert.Nil(t, err)
+	assert.Equal(t, string(data), `"=cat/pkg-1-r2:3/4=::repo[a,b,c]"`)
+	assert.Nil(t, json.Unmarshal(data, &d))
+	assert.Equal(t, d.Cmp(dep), 0)
+
+	// JSON using a specific EAPI
+	dep, _ = NewDepWithEapi("=cat/pkg-1", EAPIS["8"])
+	data, err = json.Marshal(dep)
+	assert.Nil(t, err)
+	assert.Equal(t, string(data), `{"dep":"=cat/pkg-1","eapi":"8"}`)
+	assert.Nil(t, json.Unmarshal(data, &d))
+	assert.Equal(t, d.Cmp(dep), 0)
+	data, _ = json.Marshal(&d)
+	assert.Equal(t, string(data), `{"dep":"=cat/pkg-1","eapi":"8"}`)
+
+	// zero values
+	data, err = json.Marshal(&struct{ Dep Dep }{})
+	assert.Nil(t, err)
+	assert.Equal(t, string(data), `{"Dep":""}`)
+	data, err = (*Dep)(nil).MarshalText()
+	assert.Nil(t, err)
+	assert.Equal(t, string(data), "")
+
+	// invalid
+	assert.NotNil(t, json.Unmarshal([]byte(`"cat/pkg-1"`), &d))
+	assert.NotNil(t, json.Unmarshal([]byte(`{"dep":"cat/pkg::repo","eapi":"8"}`), &d))
+	assert.NotNil(t, json.Unmarshal([]byte(`{"dep":"cat/pkg","eapi":"nonexistent"}`), &d))
+	assert.NotNil(t, json.Unmarshal([]byte(`1`), &d))
+}
+
+func TestDepSql(t *testing.T) {
+	dep, _ := NewDep("=cat/pkg-1-r2:3/4=::repo[a,b,c]")
+	val, err := dep.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, val, "=cat/pkg-1-r2:3/4=::repo[a,b,c]")
+
+	var d Dep
+	assert.Nil(t, d.Scan("=cat/pkg-1-r2:3/4=::repo[a,b,c]"))
+	assert.Equal(t, d.Cmp(dep), 0)
+	assert.Nil(t, d.Scan([]byte("=cat/pkg-1-r2:3/4=::repo[a,b,c]")))
+	assert.Equal(t, d.Cmp(dep), 0)
+
+	// NULL values
+	val, err = (*Dep)(nil).Value()
+	assert.Nil(t, err)
+	assert.Nil(t, val)
+	val, err = (&Dep{}).Value()
+	assert.Nil(t, err)
+	assert.Nil(t, val)
+	assert.Nil(t, d.Scan(nil))
+	assert.Equal(t, d, Dep{})
+
+	// invalid
+	assert.NotNil(t, d.Scan("cat/pkg-1"))
+	assert.NotNil(t, d.Scan(1))
+}
@@ -0,0 +1,234 @@
+//go:build cgo && !nocgo
+
+package pkgcraft_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/pkgcraft/pkgcraft-go"
+)
+
+func TestNewDependencySet(t *testing.T) {
+	var set *DependencySet
+	var err error
+
+	// empty
+	set, err = NewDependencySet("", DependencyUnitDep, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, set.Len(), 0)
+	assert.Equal(t, set.String(), "")
+
+	// deps
+	set, err = NewDependencySet("a/b u? ( c/d )", DependencyUnitDep, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, set.Unit(), DependencyUnitDep)
+	assert.Equal(t, set.Len(), 2)
+	assert.Equal(t, set.String(), "a/b u? ( c/d )")
+
+	// strings
+	set, err = NewDependencySet("^^ ( a b )", DependencyUnitString, EAPI_LATEST_OFFICIAL)
+	assert.Nil(t, err)
+	assert.Equal(t, set.Unit(), DependencyUnitString)
+	assert.Equal(t, set.String(), "^^ ( a b )")
+
+	// uris
+	set, err = NewDependencySet("https://a.com/b.tar.gz", DependencyUnitUri, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, set.Unit(), DependencyUnitUri)
+	assert.Equal(t, set.Len(), 1)
+
+	// invalid
+	for _, s := range []string{"(", "a/b )", "u? a/b", "=a/b"} {
+		set, err = NewDependencySet(s, DependencyUnitDep, nil)
+		assert.Nil(t, set)
+		assert.NotNil(t, err, "%s didn't fail", s)
+	}
+
+	// EAPI-specific
+	_, err = NewDependencySet("a/b::repo", DependencyUnitDep, EAPI_LATEST_OFFICIAL)
+	assert.NotNil(t, err)
+	_, err = NewDependencySet("a/b::repo", DependencyUnitDep, EAPI_LATEST)
+	assert.Nil(t, err)
+}
+
+func TestDependencyWalk(t *testing.T) {
+	pkg := newEbuildPkg(t, `EAPI=8
+DESCRIPTION="testing"
+SLOT=0
+IUSE="u u1 u2 u3 u4 u5 u6"
+DEPEND="a/b !c/d || ( e/f g/h ) u? ( i/j )"
+RDEPEND="( a/b ( c/d ) )"
+REQUIRED_USE="!u1 ?? ( u2 u3 ) ^^ ( u4 ) !u5? ( u6 )"
+`)
+	set := pkg.Depend()
+	assert.Equal(t, set.Unit(), DependencyUnitDep)
+	assert.Equal(t, set.Len(), 4)
+	deps := set.Dependencies()
+	assert.Equal(t, len(deps), 4)
+
+	// enabled
+	assert.Equal(t, deps[0].Kind(), DependencyEnabled)
+	assert.Equal(t, deps[0].Unit(), DependencyUnitDep)
+	assert.Equal(t, deps[0].Value().(*Dep).String(), "a/b")
+	assert.Nil(t, deps[0].Children())
+	assert.Equal(t, deps[0].Conditional(), "")
+
+	// blocker dep values are still enabled
+	assert.Equal(t, deps[1].Kind(), DependencyEnabled)
+	assert.Equal(t, deps[1].Value().(*Dep).Blocker(), BlockerWeak)
+
+	// any-of
+	assert.Equal(t, deps[2].Kind(), DependencyAnyOf)
+	assert.Nil(t, deps[2].Value())
+	children := deps[2].Children()
+	assert.Equal(t, len(children), 2)
+	assert.Equal(t, deps[2].Len(), 2)
+	assert.Equal(t, children[0].Value().(*Dep).String(), "e/f")
+	assert.Equal(t, children[1].Value().(*Dep).String(), "g/h")
+
+	// conditional
+	assert.Equal(t, deps[3].Kind(), DependencyConditional)
+	assert.Equal(t, deps[3].Conditional(), "u?")
+	children = deps[3].Children()
+	assert.Equal(t, len(children), 1)
+	assert.Equal(t, children[0].String(), "i/j")
+
+	// string values
+	set = pkg.RequiredUse()
+	assert.Equal(t, set.Unit(), DependencyUnitString)
+	deps = set.Dependencies()
+	assert.Equal(t, deps[0].Kind(), DependencyDisabled)
+	assert.Equal(t, deps[0].Value(), "u1")
+	assert.Equal(t, deps[1].Kind(), DependencyAtMostOneOf)
+	assert.Equal(t, deps[2].Kind(), DependencyExactlyOneOf)
+	assert.Equal(t, deps[3].Kind(), DependencyConditional)
+	assert.Equal(t, deps[3].Conditional(), "!u5?")
+
+	// all-of groups
+	deps = pkg.Rdepend().Dependencies()
+	assert.Equal(t, deps[0].Kind(), DependencyAllOf)
+	children = deps[0].Children()
+	assert.Equal(t, children[1].Kind(), DependencyAllOf)
+}
+
+func TestDependencySetEvaluate(t *testing.T) {
+	pkg := newEbuildPkg(t, `EAPI=8
+DESCRIPTION="testing"
+SLOT=0
+IUSE="u1 u2 u3"
+DEPEND="a/b u1? ( c/d ) !u2? ( e/f ) u3? ( || ( g/h i/j ) )"
+`)
+	set := pkg.Depend()
+
+	// no enabled flags
+	assert.Equal(t, set.Evaluate([]string{}).String(), "a/b e/f")
+
+	// enabled flags
+	assert.Equal(t, set.Evaluate([]string{"u1", "u2"}).String(), "a/b c/d")
+
+	// any-of groups are retained
+	evaluated := set.Evaluate([]string{"u2", "u3"})
+	assert.Equal(t, evaluated.String(), "a/b || ( g/h i/j )")
+	deps := evaluated.Dependencies()
+	assert.Equal(t, deps[1].Kind(), DependencyAnyOf)
+}
+
+func TestDependencySetFlatten(t *testing.T) {
+	// deps
+	set, _ := NewDependencySet("a/b || ( c/d u? ( e/f ) ) !u? ( ( g/h ) )", DependencyUnitDep, nil)
+	var deps []string
+	for _, dep := range set.Deps() {
+		deps = append(deps, dep.String())
+	}
+	assert.Equal(t, deps, []string{"a/b", "c/d", "e/f", "g/h"})
+	assert.Nil(t, set.Strings())
+	assert.Nil(t, set.Uris())
+
+	// strings
+	set, _ = NewDependencySet("a || ( b u? ( c ) )", DependencyUnitString, nil)
+	assert.Equal(t, set.Strings(), []string{"a", "b", "c"})
+	assert.Nil(t, set.Deps())
+
+	// uris
+	set, _ = NewDependencySet("https://a.com/b.tar.gz u? ( https://c.com/d.tar.gz )", DependencyUnitUri, nil)
+	var uris []string
+	for _, uri := range set.Uris() {
+		uris = append(uris, uri.String())
+	}
+	assert.Equal(t, uris, []string{"https://a.com/b.tar.gz", "https://c.com/d.tar.gz"})
+	assert.Nil(t, set.Deps())
+}
+
+func TestDependencySetEqual(t *testing.T) {
+	s1, _ := NewDependencySet("a/b c/d", DependencyUnitDep, nil)
+	s2, _ := NewDependencySet("c/d a/b", DependencyUnitDep, nil)
+	s3, _ := NewDependencySet("a/b", DependencyUnitDep, nil)
+
+	// ordering is ignored
+	assert.True(t, s1.Equal(s2))
+	assert.Equal(t, s1.Hash(), s2.Hash())
+
+	// unequal
+	assert.False(t, s1.Equal(s3))
+	assert.NotEqual(t, s1.Hash(), s3.Hash())
+}
+
+func TestDependencySetContains(t *testing.T) {
+	set, _ := NewDependencySet("a/b u? ( c/d )", DependencyUnitDep, nil)
+
+	// top-level dependencies
+	dep, _ := NewDep("a/b")
+	assert.True(t, set.Contains(dep))
+	for _, d := range set.Dependencies() {
+		assert.True(t, set.Contains(d))
+	}
+
+	// nested dependencies
+	dep, _ = NewDep("c/d")
+	assert.True(t, set.Contains(dep))
+
+	// missing dependencies
+	dep, _ = NewDep("e/f")
+	assert.False(t, set.Contains(dep))
+
+	// strings
+	set, _ = NewDependencySet("a u? ( b )", DependencyUnitString, nil)
+	assert.True(t, set.Contains("a"))
+	assert.True(t, set.Contains("b"))
+	assert.False(t, set.Contains("c"))
+
+	// unsupported types
+	assert.False(t, set.Contains(1))
+}
+
+func TestDependencySetOps(t *testing.T) {
+	s1, _ := NewDependencySet("a/b c/d", DependencyUnitDep, nil)
+	s2, _ := NewDependencySet("c/d e/f", DependencyUnitDep, nil)
+
+	set, err := s1.Union(s2)
+	assert.Nil(t, err)
+	assert.Equal(t, set.String(), "a/b c/d e/f")
+
+	set, err = s1.Intersection(s2)
+	assert.Nil(t, err)
+	assert.Equal(t, set.String(), "c/d")
+
+	set, err = s1.Difference(s2)
+	assert.Nil(t, err)
+	assert.Equal(t, set.String(), "a/b")
+
+	set, err = s1.SymmetricDifference(s2)
+	assert.Nil(t, err)
+	assert.Equal(t, set.String(), "a/b e/f")
+
+	// original sets are unmodified
+	assert.Equal(t, s1.String(), "a/b c/d")
+	assert.Equal(t, s2.String(), "c/d e/f")
+
+	// mismatched units
+	s3, _ := NewDependencySet("a b", DependencyUnitString, nil)
+	_, err = s1.Union(s3)
+	assert.NotNil(t, err)
+}
@@ -1,3 +1,5 @@
+//go:build cgo && !nocgo
+
 package pkgcraft
 
 // #cgo pkg-config: pkgcraft
@@ -5,6 +7,7 @@ package pkgcraft
 import "C"
 
 import (
+	"fmt"
 	"unsafe"
 )
 
@@ -103,6 +106,20 @@ func (self *Eapi) String() string {
 	return self.id
 }
 
+func (self *Eapi) MarshalText() ([]byte, error) {
+	return []byte(self.id), nil
+}
+
+// Unmarshal an EAPI from text. Note that this copies the related global Eapi
+// object so pointer comparisons with EAPIS entries will fail, use Cmp() instead.
+func (self *Eapi) UnmarshalText(data []byte) error {
+	if eapi, ok := EAPIS[string(data)]; ok {
+		*self = *eapi
+		return nil
+	}
+	return fmt.Errorf("unknown EAPI: %s", data)
+}
+
 // Check if an EAPI has a given feature.
 func (self *Eapi) Has(s string) bool {
 	cstr := C.CString(s)
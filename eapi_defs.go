@@ -0,0 +1,66 @@
+package pkgcraft
+
+import (
+	"sort"
+)
+
+// EAPI definitions mirroring those supported by pkgcraft, ordered
+// chronologically. These are used natively by nocgo builds and verified against
+// pkgcraft by the cgo tests.
+var eapiDefs = []struct {
+	id       string
+	official bool
+	features []string
+	depKeys  []string
+}{
+	{"5", true, nil, []string{"DEPEND", "PDEPEND", "RDEPEND"}},
+	{"6", true, []string{"NonfatalDie", "GlobalFailglob", "UnpackExtendedPath", "UnpackCaseInsensitive"}, nil},
+	{"7", true, []string{"QueryHostRoot", "QueryDeps"}, []string{"BDEPEND"}},
+	{"8", true, []string{"ConsistentFileOpts", "DosymRelative", "SrcUriUnrestrict", "UsevTwoArgs"}, []string{"IDEPEND"}},
+	{"pkgcraft", false, []string{"RepoIds"}, nil},
+}
+
+// Metadata keys supported by all EAPIs, excluding dependency keys.
+var eapiMetadataKeys = []string{
+	"DEFINED_PHASES", "DESCRIPTION", "EAPI", "HOMEPAGE", "INHERIT", "INHERITED", "IUSE",
+	"KEYWORDS", "LICENSE", "PROPERTIES", "REQUIRED_USE", "RESTRICT", "SLOT", "SRC_URI",
+}
+
+// EAPI definition including the features and keys inherited from previous EAPIs.
+type eapiSpec struct {
+	id           string
+	official     bool
+	features     map[string]bool
+	depKeys      []string
+	metadataKeys []string
+}
+
+// Return the EAPI specifications in chronological order, inheriting features
+// and dependency keys from previous EAPIs.
+func eapiSpecs() []eapiSpec {
+	var specs []eapiSpec
+	features := make(map[string]bool)
+	var depKeys []string
+	for _, def := range eapiDefs {
+		for _, s := range def.features {
+			features[s] = true
+		}
+		depKeys = append(depKeys, def.depKeys...)
+
+		spec := eapiSpec{id: def.id, official: def.official, features: make(map[string]bool)}
+		for s := range features {
+			spec.features[s] = true
+		}
+		spec.depKeys = sortedStrings(depKeys)
+		spec.metadataKeys = sortedStrings(append(eapiMetadataKeys, depKeys...))
+		specs = append(specs, spec)
+	}
+	return specs
+}
+
+// Return a sorted copy of a slice of strings.
+func sortedStrings(vals []string) []string {
+	sorted := append([]string(nil), vals...)
+	sort.Strings(sorted)
+	return sorted
+}
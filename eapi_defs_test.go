@@ -0,0 +1,42 @@
+//go:build cgo && !nocgo
+
+package pkgcraft
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The EAPI definitions used by nocgo builds must match pkgcraft.
+func TestEapiDefs(t *testing.T) {
+	specs := eapiSpecs()
+	eapis, err := EapiRange("..")
+	assert.Nil(t, err)
+	assert.Equal(t, len(eapis), len(specs))
+	assert.Equal(t, len(EAPIS), len(specs))
+
+	features := make(map[string]bool)
+	for _, def := range eapiDefs {
+		for _, s := range def.features {
+			features[s] = true
+		}
+	}
+
+	for i, spec := range specs {
+		eapi := EAPIS[spec.id]
+		if !assert.NotNil(t, eapi, "unknown EAPI: %s", spec.id) {
+			continue
+		}
+		if i < len(eapis) {
+			assert.Equal(t, eapis[i].String(), spec.id, "EAPI %s out of order", spec.id)
+		}
+		_, official := EAPIS_OFFICIAL[spec.id]
+		assert.Equal(t, official, spec.official, "EAPI %s official status", spec.id)
+		for s := range features {
+			assert.Equal(t, eapi.Has(s), spec.features[s], "EAPI %s feature %s", spec.id, s)
+		}
+		assert.Equal(t, sortedStrings(eapi.DepKeys()), spec.depKeys, "EAPI %s dep keys", spec.id)
+		assert.Equal(t, sortedStrings(eapi.MetadataKeys()), spec.metadataKeys, "EAPI %s metadata keys", spec.id)
+	}
+}
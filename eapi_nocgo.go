@@ -0,0 +1,135 @@
+//go:build !cgo || nocgo
+
+package pkgcraft
+
+import (
+	"fmt"
+	"strings"
+)
+
+var eapis_ordered = getOrderedEapis()
+var EAPIS_OFFICIAL = getOfficialEapis()
+var EAPIS = getEapis()
+var EAPI_LATEST_OFFICIAL *Eapi
+var EAPI_LATEST *Eapi
+
+// Return all known EAPIs in chronological order.
+func getOrderedEapis() []*Eapi {
+	var eapis []*Eapi
+	for i, spec := range eapiSpecs() {
+		eapi := &Eapi{id: spec.id, idx: i, features: spec.features}
+		eapi.depKeys = spec.depKeys
+		eapi.metadataKeys = spec.metadataKeys
+		eapis = append(eapis, eapi)
+	}
+	return eapis
+}
+
+// Return the mapping of all official EAPIs.
+func getOfficialEapis() map[string]*Eapi {
+	m := make(map[string]*Eapi)
+	for i, eapi := range eapis_ordered {
+		if eapiDefs[i].official {
+			m[eapi.id] = eapi
+			// set global alias for the most recent, official EAPI
+			EAPI_LATEST_OFFICIAL = eapi
+		}
+	}
+	return m
+}
+
+// Return the mapping of all known EAPIs.
+func getEapis() map[string]*Eapi {
+	m := make(map[string]*Eapi)
+	for _, eapi := range eapis_ordered {
+		m[eapi.id] = eapi
+	}
+
+	// set global alias for the most recent EAPI
+	EAPI_LATEST = eapis_ordered[len(eapis_ordered)-1]
+	return m
+}
+
+// Convert an EAPI range into an array of Eapi objects.
+func EapiRange(s string) ([]*Eapi, error) {
+	start_id, end_id, found := strings.Cut(s, "..")
+	if !found {
+		return nil, &PkgcraftError{fmt.Sprintf("invalid EAPI range: %s", s)}
+	}
+
+	start := 0
+	end := len(eapis_ordered)
+	if start_id != "" {
+		eapi, ok := EAPIS[start_id]
+		if !ok {
+			return nil, &PkgcraftError{fmt.Sprintf("unknown EAPI: %s", start_id)}
+		}
+		start = eapi.idx
+	}
+	if end_id != "" {
+		inclusive := strings.HasPrefix(end_id, "=")
+		end_id = strings.TrimPrefix(end_id, "=")
+		eapi, ok := EAPIS[end_id]
+		if !ok {
+			return nil, &PkgcraftError{fmt.Sprintf("unknown EAPI: %s", end_id)}
+		}
+		end = eapi.idx
+		if inclusive {
+			end++
+		}
+	}
+
+	var eapis []*Eapi
+	for i := start; i < end; i++ {
+		eapis = append(eapis, eapis_ordered[i])
+	}
+	return eapis, nil
+}
+
+type Eapi struct {
+	id           string
+	idx          int
+	features     map[string]bool
+	depKeys      []string
+	metadataKeys []string
+}
+
+// Return the string for an EAPI.
+func (self *Eapi) String() string {
+	return self.id
+}
+
+func (self *Eapi) MarshalText() ([]byte, error) {
+	return []byte(self.id), nil
+}
+
+// Unmarshal an EAPI from text. Note that this copies the related global Eapi
+// object so pointer comparisons with EAPIS entries will fail, use Cmp() instead.
+func (self *Eapi) UnmarshalText(data []byte) error {
+	if eapi, ok := EAPIS[string(data)]; ok {
+		*self = *eapi
+		return nil
+	}
+	return fmt.Errorf("unknown EAPI: %s", data)
+}
+
+// Check if an EAPI has a given feature.
+func (self *Eapi) Has(s string) bool {
+	return self.features[s]
+}
+
+// Get an EAPI's dependency keys.
+func (self *Eapi) DepKeys() []string {
+	return append([]string(nil), self.depKeys...)
+}
+
+// Get an EAPI's metadata keys.
+func (self *Eapi) MetadataKeys() []string {
+	return append([]string(nil), self.metadataKeys...)
+}
+
+// Compare an Eapi with another Eapi chronologically returning -1, 0, or 1 if
+// the first is less than, equal to, or greater than the second, respectively.
+func (self *Eapi) Cmp(other *Eapi) int {
+	return cmpInts(self.idx, other.idx)
+}
@@ -1,6 +1,7 @@
 package pkgcraft_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -66,3 +67,16 @@ func TestEapiCmp(t *testing.T) {
 	assert.Equal(t, EAPIS["8"].Cmp(EAPIS["8"]), 0)
 	assert.Equal(t, EAPIS["8"].Cmp(EAPIS["7"]), 1)
 }
+
+func TestEapiMarshal(t *testing.T) {
+	data, err := json.Marshal(EAPIS["8"])
+	assert.Nil(t, err)
+	assert.Equal(t, string(data), `"8"`)
+
+	var eapi Eapi
+	assert.Nil(t, json.Unmarshal(data, &eapi))
+	assert.Equal(t, eapi.Cmp(EAPIS["8"]), 0)
+
+	// invalid
+	assert.NotNil(t, json.Unmarshal([]byte(`"nonexistent"`), &eapi))
+}
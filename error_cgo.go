@@ -0,0 +1,25 @@
+//go:build cgo && !nocgo
+
+package pkgcraft
+
+// #cgo pkg-config: pkgcraft
+// #include <pkgcraft.h>
+import "C"
+
+func newPkgcraftError() error {
+	if err := lastPkgcraftError(); err != nil {
+		return err
+	} else {
+		panic("no pkgcraft error occurred")
+	}
+}
+
+// Return the last pkgcraft error if one occurred, otherwise nil.
+func lastPkgcraftError() error {
+	err := C.pkgcraft_error_last()
+	if err != nil {
+		defer C.pkgcraft_error_free(err)
+		return &PkgcraftError{C.GoString(err.message)}
+	}
+	return nil
+}
@@ -1,3 +1,5 @@
+//go:build cgo && !nocgo
+
 package pkgcraft
 
 // #cgo pkg-config: pkgcraft
@@ -5,9 +7,16 @@ package pkgcraft
 import "C"
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"runtime"
+	"sort"
 )
 
+// Error returned when a requested package doesn't exist in a repo.
+var ErrPkgNotFound = errors.New("package not found")
+
 type RepoFormat int
 
 const (
@@ -24,10 +33,59 @@ type pkgRepo[P Pkg] interface {
 	createPkg(*C.Pkg) P
 }
 
+// Error for packages that failed to load, results returned alongside it are
+// still valid.
+type PkgLoadError struct {
+	Errs []error
+}
+
+func (self *PkgLoadError) Error() string {
+	return errors.Join(self.Errs...).Error()
+}
+
+func (self *PkgLoadError) Unwrap() []error {
+	return self.Errs
+}
+
+// Return an error for packages that failed to load, nil if none failed.
+func newPkgLoadError(errs []error) error {
+	if len(errs) > 0 {
+		return &PkgLoadError{errs}
+	}
+	return nil
+}
+
+// Iterator over the packages of a repo.
+type PkgIterator[P Pkg] interface {
+	// Determine if the iterator has another entry, packages that fail to load
+	// are skipped.
+	HasNext() bool
+	// Return the next available package in the iterator.
+	Next() P
+	// Return a *PkgLoadError for packages that failed to load during
+	// iteration, nil if none failed.
+	Err() error
+	// Release the iterator's resources, further iteration yields no packages.
+	Close()
+}
+
+// Function iterating over packages along with their load errors, compatible
+// with iter.Seq2. Note that ranging over it via for loops requires Go 1.23 or
+// later, otherwise call it directly with a yield function.
+type PkgSeq[P Pkg] func(yield func(P, error) bool)
+
+// Package iterator supporting access to individual load errors.
+type pkgIter[P Pkg] interface {
+	PkgIterator[P]
+	// Return the next package or its load error, false if exhausted.
+	advance() (P, bool, error)
+}
+
 type repoIter[P Pkg] struct {
 	ptr  *C.RepoIter
 	repo pkgRepo[P]
 	next P
+	errs []error
 }
 
 // Create an iterator over the packages of a repo.
@@ -38,14 +96,31 @@ func newRepoIter[P Pkg](repo pkgRepo[P]) *repoIter[P] {
 	return iter
 }
 
-// Determine if a package iterator has another entry.
+// Return the next package or its load error, false if exhausted.
+func (self *repoIter[P]) advance() (pkg P, ok bool, err error) {
+	if self.ptr == nil {
+		return pkg, false, nil
+	} else if ptr := C.pkgcraft_repo_iter_next(self.ptr); ptr != nil {
+		return self.repo.createPkg(ptr), true, nil
+	} else if err := lastPkgcraftError(); err != nil {
+		return pkg, true, err
+	}
+	return pkg, false, nil
+}
+
+// Determine if a package iterator has another entry, packages that fail to
+// load are skipped with their errors available via Err().
 func (self *repoIter[P]) HasNext() bool {
-	ptr := C.pkgcraft_repo_iter_next(self.ptr)
-	if ptr != nil {
-		self.next = self.repo.createPkg(ptr)
-		return true
-	} else {
-		return false
+	for {
+		pkg, ok, err := self.advance()
+		if !ok {
+			return false
+		} else if err != nil {
+			self.errs = append(self.errs, err)
+		} else {
+			self.next = pkg
+			return true
+		}
 	}
 }
 
@@ -54,22 +129,93 @@ func (self *repoIter[P]) Next() P {
 	return self.next
 }
 
-// Return a generic channel iterating over the packages of a repo.
-func repoPkgs[P Pkg](repo pkgRepo[P]) <-chan P {
+// Return a *PkgLoadError for packages that failed to load during iteration.
+func (self *repoIter[P]) Err() error {
+	return newPkgLoadError(self.errs)
+}
+
+// Free the underlying C iterator, further iteration yields no packages.
+func (self *repoIter[P]) Close() {
+	if self.ptr != nil {
+		C.pkgcraft_repo_iter_free(self.ptr)
+		self.ptr = nil
+		runtime.SetFinalizer(self, nil)
+	}
+}
+
+// Return a function iterating over the packages of an iterator along with
+// their load errors.
+func iterSeq[P Pkg](newIter func() pkgIter[P]) PkgSeq[P] {
+	return func(yield func(P, error) bool) {
+		iter := newIter()
+		defer iter.Close()
+		for {
+			pkg, ok, err := iter.advance()
+			if !ok || !yield(pkg, err) {
+				return
+			}
+		}
+	}
+}
+
+// Return a channel iterating over packages that is closed at the first load
+// error.
+func seqChan[P Pkg](seq PkgSeq[P]) <-chan P {
 	pkgs := make(chan P)
 	go func() {
-		for iter := newRepoIter[P](repo); iter.HasNext(); {
-			pkgs <- iter.Next()
-		}
-		close(pkgs)
+		defer close(pkgs)
+		seq(func(pkg P, err error) bool {
+			if err != nil {
+				return false
+			}
+			pkgs <- pkg
+			return true
+		})
 	}()
 	return pkgs
 }
 
+// Return a channel iterating over packages that is closed when they're
+// exhausted or the context is cancelled, along with a function returning a
+// *PkgLoadError for packages that failed to load. The error function blocks
+// until iteration ends, i.e. until the channel is drained or the context is
+// cancelled.
+func seqChanContext[P Pkg](ctx context.Context, seq PkgSeq[P]) (<-chan P, func() error) {
+	pkgs := make(chan P)
+	done := make(chan struct{})
+	var errs []error
+	go func() {
+		defer close(pkgs)
+		defer close(done)
+		seq(func(pkg P, err error) bool {
+			if err != nil {
+				errs = append(errs, err)
+				return true
+			}
+			select {
+			case pkgs <- pkg:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+	}()
+	return pkgs, func() error {
+		<-done
+		return newPkgLoadError(errs)
+	}
+}
+
+// Return a generic function iterating over the packages of a repo.
+func repoPkgs[P Pkg](repo pkgRepo[P]) PkgSeq[P] {
+	return iterSeq(func() pkgIter[P] { return newRepoIter[P](repo) })
+}
+
 type repoIterRestrict[P Pkg] struct {
 	ptr  *C.RepoIterRestrict
 	repo pkgRepo[P]
 	next P
+	errs []error
 }
 
 // Create a restricted iterator over the packages of a repo.
@@ -80,14 +226,31 @@ func newRepoIterRestrict[P Pkg](repo pkgRepo[P], restrict *Restrict) *repoIterRe
 	return iter
 }
 
-// Determine if a restricted package iterator has another entry.
+// Return the next package or its load error, false if exhausted.
+func (self *repoIterRestrict[P]) advance() (pkg P, ok bool, err error) {
+	if self.ptr == nil {
+		return pkg, false, nil
+	} else if ptr := C.pkgcraft_repo_iter_restrict_next(self.ptr); ptr != nil {
+		return self.repo.createPkg(ptr), true, nil
+	} else if err := lastPkgcraftError(); err != nil {
+		return pkg, true, err
+	}
+	return pkg, false, nil
+}
+
+// Determine if a restricted package iterator has another entry, packages that
+// fail to load are skipped with their errors available via Err().
 func (self *repoIterRestrict[P]) HasNext() bool {
-	ptr := C.pkgcraft_repo_iter_restrict_next(self.ptr)
-	if ptr != nil {
-		self.next = self.repo.createPkg(ptr)
-		return true
-	} else {
-		return false
+	for {
+		pkg, ok, err := self.advance()
+		if !ok {
+			return false
+		} else if err != nil {
+			self.errs = append(self.errs, err)
+		} else {
+			self.next = pkg
+			return true
+		}
 	}
 }
 
@@ -96,16 +259,72 @@ func (self *repoIterRestrict[P]) Next() P {
 	return self.next
 }
 
-// Return a generic channel iterating over the restricted packages of a repo.
-func repoRestrictPkgs[P Pkg](repo pkgRepo[P], restrict *Restrict) <-chan P {
-	pkgs := make(chan P)
+// Return a *PkgLoadError for packages that failed to load during iteration.
+func (self *repoIterRestrict[P]) Err() error {
+	return newPkgLoadError(self.errs)
+}
 
-	go func(restrict *Restrict) {
-		for iter := newRepoIterRestrict[P](repo, restrict); iter.HasNext(); {
-			pkgs <- iter.Next()
-		}
-		close(pkgs)
-	}(restrict)
+// Free the underlying C iterator, further iteration yields no packages.
+func (self *repoIterRestrict[P]) Close() {
+	if self.ptr != nil {
+		C.pkgcraft_repo_iter_restrict_free(self.ptr)
+		self.ptr = nil
+		runtime.SetFinalizer(self, nil)
+	}
+}
 
-	return pkgs
+// Return a generic function iterating over the restricted packages of a repo.
+func repoRestrictPkgs[P Pkg](repo pkgRepo[P], restrict *Restrict) PkgSeq[P] {
+	return iterSeq(func() pkgIter[P] { return newRepoIterRestrict[P](repo, restrict) })
+}
+
+// Return the package matching a Cpv from a repo.
+func repoGet[P Pkg](repo pkgRepo[P], cpv *Cpv) (P, error) {
+	var pkg P
+	restrict, err := NewRestrict(cpv)
+	if err != nil {
+		return pkg, err
+	}
+	iter := newRepoIterRestrict[P](repo, restrict)
+	defer iter.Close()
+	if iter.HasNext() {
+		return iter.Next(), nil
+	} else if err := iter.Err(); err != nil {
+		return pkg, err
+	}
+	return pkg, fmt.Errorf("%w: %s", ErrPkgNotFound, cpv)
+}
+
+// Return the packages matching a dependency from a repo sorted by version,
+// along with a non-fatal *PkgLoadError for matching packages that failed to
+// load.
+func repoMatches[P Pkg](repo pkgRepo[P], dep *Dep) ([]P, error) {
+	restrict, err := NewRestrict(dep)
+	if err != nil {
+		return nil, err
+	}
+	var pkgs []P
+	iter := newRepoIterRestrict[P](repo, restrict)
+	defer iter.Close()
+	for iter.HasNext() {
+		pkgs = append(pkgs, iter.Next())
+	}
+	sort.SliceStable(pkgs, func(i, j int) bool { return pkgs[i].Version().Cmp(pkgs[j].Version()) < 0 })
+	return pkgs, iter.Err()
+}
+
+// Return the highest versioned package matching a dependency from a repo,
+// along with a non-fatal *PkgLoadError for matching packages that failed to
+// load.
+func repoBestMatch[P Pkg](repo pkgRepo[P], dep *Dep) (P, error) {
+	var pkg P
+	pkgs, err := repoMatches(repo, dep)
+	if len(pkgs) > 0 {
+		return pkgs[len(pkgs)-1], err
+	}
+	var loadErr *PkgLoadError
+	if err != nil && !errors.As(err, &loadErr) {
+		return pkg, err
+	}
+	return pkg, errors.Join(fmt.Errorf("%w: %s", ErrPkgNotFound, dep), err)
 }
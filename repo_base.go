@@ -1,3 +1,5 @@
+//go:build cgo && !nocgo
+
 package pkgcraft
 
 // #cgo pkg-config: pkgcraft
@@ -5,6 +7,7 @@ package pkgcraft
 import "C"
 
 import (
+	"context"
 	"runtime"
 	"unsafe"
 )
@@ -42,6 +45,40 @@ func (self *BaseRepo) Len() int {
 	return int(C.pkgcraft_repo_len(self.ptr))
 }
 
+// Return a repo's categories in sorted order.
+func (self *BaseRepo) Categories() []string {
+	var length C.size_t
+	array := C.pkgcraft_repo_categories(self.ptr, &length)
+	return charArrayToSlice(array, length)
+}
+
+// Return the packages of a category in sorted order.
+func (self *BaseRepo) Packages(category string) []string {
+	var length C.size_t
+	c_cat := C.CString(category)
+	defer C.free(unsafe.Pointer(c_cat))
+	array := C.pkgcraft_repo_packages(self.ptr, c_cat, &length)
+	return charArrayToSlice(array, length)
+}
+
+// Return the versions of a package in sorted order.
+func (self *BaseRepo) Versions(category, pkg string) []*Version {
+	var length C.size_t
+	c_cat := C.CString(category)
+	defer C.free(unsafe.Pointer(c_cat))
+	c_pkg := C.CString(pkg)
+	defer C.free(unsafe.Pointer(c_pkg))
+	array := C.pkgcraft_repo_versions(self.ptr, c_cat, c_pkg, &length)
+	var versions []*Version
+	for _, ptr := range unsafe.Slice(array, length) {
+		// versions originate from valid packages so creation can't fail
+		ver, _ := versionFromPtr(ptr)
+		versions = append(versions, ver)
+	}
+	C.pkgcraft_array_free((*unsafe.Pointer)(unsafe.Pointer(array)), length)
+	return versions
+}
+
 func (self *BaseRepo) String() string {
 	return self.Id()
 }
@@ -60,25 +97,79 @@ func (self *BaseRepo) createPkg(ptr *C.Pkg) *BasePkg {
 }
 
 // Return an iterator over the packages of a repo.
-func (self *BaseRepo) Iter() *repoIter[*BasePkg] {
+func (self *BaseRepo) Iter() PkgIterator[*BasePkg] {
 	return newRepoIter[*BasePkg](self)
 }
 
 // Return a channel iterating over the packages of a repo.
+//
+// The channel is closed at the first package that fails to load and must be
+// drained to release its resources, use PkgsContext() or PkgsSeq() to report
+// load errors or stop early.
 func (self *BaseRepo) Pkgs() <-chan *BasePkg {
+	return seqChan(self.PkgsSeq())
+}
+
+// Return a channel iterating over the packages of a repo that is closed when
+// the context is cancelled, along with a function returning a *PkgLoadError
+// for packages that failed to load which blocks until iteration ends.
+func (self *BaseRepo) PkgsContext(ctx context.Context) (<-chan *BasePkg, func() error) {
+	return seqChanContext(ctx, self.PkgsSeq())
+}
+
+// Return a function iterating over the packages of a repo along with their
+// load errors.
+func (self *BaseRepo) PkgsSeq() PkgSeq[*BasePkg] {
 	return repoPkgs[*BasePkg](self)
 }
 
 // Return an iterator over the restricted packages of a repo.
-func (self *BaseRepo) IterRestrict(restrict *Restrict) *repoIterRestrict[*BasePkg] {
+func (self *BaseRepo) IterRestrict(restrict *Restrict) PkgIterator[*BasePkg] {
 	return newRepoIterRestrict[*BasePkg](self, restrict)
 }
 
 // Return a channel iterating over the restricted packages of a repo.
+//
+// The channel is closed at the first package that fails to load and must be
+// drained to release its resources, use RestrictPkgsContext() or
+// RestrictPkgsSeq() to report load errors or stop early.
 func (self *BaseRepo) RestrictPkgs(restrict *Restrict) <-chan *BasePkg {
+	return seqChan(self.RestrictPkgsSeq(restrict))
+}
+
+// Return a channel iterating over the restricted packages of a repo that is
+// closed when the context is cancelled, along with a function returning a
+// *PkgLoadError for packages that failed to load which blocks until iteration
+// ends.
+func (self *BaseRepo) RestrictPkgsContext(ctx context.Context, restrict *Restrict) (<-chan *BasePkg, func() error) {
+	return seqChanContext(ctx, self.RestrictPkgsSeq(restrict))
+}
+
+// Return a function iterating over the restricted packages of a repo along
+// with their load errors.
+func (self *BaseRepo) RestrictPkgsSeq(restrict *Restrict) PkgSeq[*BasePkg] {
 	return repoRestrictPkgs[*BasePkg](self, restrict)
 }
 
+// Return the package matching a Cpv, returning an error wrapping
+// ErrPkgNotFound if it doesn't exist.
+func (self *BaseRepo) Get(cpv *Cpv) (*BasePkg, error) {
+	return repoGet[*BasePkg](self, cpv)
+}
+
+// Return the packages matching a dependency sorted by version, along with a
+// non-fatal *PkgLoadError for matching packages that failed to load.
+func (self *BaseRepo) Matches(dep *Dep) ([]*BasePkg, error) {
+	return repoMatches[*BasePkg](self, dep)
+}
+
+// Return the highest versioned package matching a dependency, returning an
+// error wrapping ErrPkgNotFound if no packages match. Matching packages that
+// failed to load are reported via a non-fatal *PkgLoadError.
+func (self *BaseRepo) BestMatch(dep *Dep) (*BasePkg, error) {
+	return repoBestMatch[*BasePkg](self, dep)
+}
+
 // Return true if a repo contains a given object, false otherwise.
 func (self *BaseRepo) Contains(obj interface{}) bool {
 	switch obj := obj.(type) {
@@ -87,9 +178,9 @@ func (self *BaseRepo) Contains(obj interface{}) bool {
 		defer C.free(unsafe.Pointer(c_str))
 		return bool(C.pkgcraft_repo_contains_path(self.ptr, c_str))
 	case *Restrict:
-		pkgs := self.RestrictPkgs(obj)
-		_, ok := <-pkgs
-		return ok
+		iter := newRepoIterRestrict[*BasePkg](self, obj)
+		defer iter.Close()
+		return iter.HasNext()
 	default:
 		if restrict, _ := NewRestrict(obj); restrict != nil {
 			return self.Contains(restrict)
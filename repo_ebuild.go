@@ -1,3 +1,5 @@
+//go:build cgo && !nocgo
+
 package pkgcraft
 
 // #cgo pkg-config: pkgcraft
@@ -5,6 +7,8 @@ package pkgcraft
 import "C"
 
 import (
+	"context"
+	"errors"
 	"runtime"
 )
 
@@ -33,21 +37,91 @@ func (self *EbuildRepo) createPkg(ptr *C.Pkg) *EbuildPkg {
 }
 
 // Return an iterator over the packages of a repo.
-func (self *EbuildRepo) Iter() *repoIter[*EbuildPkg] {
+func (self *EbuildRepo) Iter() PkgIterator[*EbuildPkg] {
 	return newRepoIter[*EbuildPkg](self)
 }
 
 // Return a channel iterating over the packages of a repo.
+//
+// The channel is closed at the first package that fails to load and must be
+// drained to release its resources, use PkgsContext() or PkgsSeq() to report
+// load errors or stop early.
 func (self *EbuildRepo) Pkgs() <-chan *EbuildPkg {
+	return seqChan(self.PkgsSeq())
+}
+
+// Return a channel iterating over the packages of a repo that is closed when
+// the context is cancelled, along with a function returning a *PkgLoadError
+// for packages that failed to load which blocks until iteration ends.
+func (self *EbuildRepo) PkgsContext(ctx context.Context) (<-chan *EbuildPkg, func() error) {
+	return seqChanContext(ctx, self.PkgsSeq())
+}
+
+// Return a function iterating over the packages of a repo along with their
+// load errors.
+func (self *EbuildRepo) PkgsSeq() PkgSeq[*EbuildPkg] {
 	return repoPkgs[*EbuildPkg](self)
 }
 
 // Return an iterator over the restricted packages of a repo.
-func (self *EbuildRepo) IterRestrict(restrict *Restrict) *repoIterRestrict[*EbuildPkg] {
+func (self *EbuildRepo) IterRestrict(restrict *Restrict) PkgIterator[*EbuildPkg] {
 	return newRepoIterRestrict[*EbuildPkg](self, restrict)
 }
 
 // Return a channel iterating over the restricted packages of a repo.
+//
+// The channel is closed at the first package that fails to load and must be
+// drained to release its resources, use RestrictPkgsContext() or
+// RestrictPkgsSeq() to report load errors or stop early.
 func (self *EbuildRepo) RestrictPkgs(restrict *Restrict) <-chan *EbuildPkg {
+	return seqChan(self.RestrictPkgsSeq(restrict))
+}
+
+// Return a channel iterating over the restricted packages of a repo that is
+// closed when the context is cancelled, along with a function returning a
+// *PkgLoadError for packages that failed to load which blocks until iteration
+// ends.
+func (self *EbuildRepo) RestrictPkgsContext(ctx context.Context, restrict *Restrict) (<-chan *EbuildPkg, func() error) {
+	return seqChanContext(ctx, self.RestrictPkgsSeq(restrict))
+}
+
+// Return a function iterating over the restricted packages of a repo along
+// with their load errors.
+func (self *EbuildRepo) RestrictPkgsSeq(restrict *Restrict) PkgSeq[*EbuildPkg] {
 	return repoRestrictPkgs[*EbuildPkg](self, restrict)
 }
+
+// Return the package matching a Cpv, returning an error wrapping
+// ErrPkgNotFound if it doesn't exist.
+func (self *EbuildRepo) Get(cpv *Cpv) (*EbuildPkg, error) {
+	return repoGet[*EbuildPkg](self, cpv)
+}
+
+// Return the packages matching a dependency sorted by version, along with a
+// non-fatal *PkgLoadError for matching packages that failed to load.
+func (self *EbuildRepo) Matches(dep *Dep) ([]*EbuildPkg, error) {
+	return repoMatches[*EbuildPkg](self, dep)
+}
+
+// Return the highest versioned package matching a dependency, returning an
+// error wrapping ErrPkgNotFound if no packages match. Matching packages that
+// failed to load are reported via a non-fatal *PkgLoadError.
+func (self *EbuildRepo) BestMatch(dep *Dep) (*EbuildPkg, error) {
+	return repoBestMatch[*EbuildPkg](self, dep)
+}
+
+// Return the highest versioned package for each slot matching a dependency,
+// along with a non-fatal *PkgLoadError for matching packages that failed to
+// load.
+func (self *EbuildRepo) BestMatchBySlot(dep *Dep) (map[string]*EbuildPkg, error) {
+	pkgs, err := self.Matches(dep)
+	var loadErr *PkgLoadError
+	if err != nil && !errors.As(err, &loadErr) {
+		return nil, err
+	}
+	slots := make(map[string]*EbuildPkg)
+	for _, pkg := range pkgs {
+		slots[pkg.Slot()] = pkg
+	}
+	return slots, err
+}
@@ -0,0 +1,288 @@
+//go:build cgo && !nocgo
+
+package pkgcraft
+
+// #cgo pkg-config: pkgcraft
+// #include <pkgcraft.h>
+import "C"
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"unsafe"
+)
+
+// Return an ebuild repo's masters in inheritance order, as resolved from its
+// metadata/layout.conf file against the repos of its config.
+func (self *EbuildRepo) Masters() []*EbuildRepo {
+	var length C.size_t
+	c_repos := C.pkgcraft_repo_ebuild_masters(self.ptr, &length)
+	var repos []*EbuildRepo
+	for _, ptr := range unsafe.Slice(c_repos, length) {
+		repo := &EbuildRepo{repoFromPtr(ptr), nil}
+		runtime.SetFinalizer(repo, func(self *EbuildRepo) { C.pkgcraft_repo_free(self.ptr) })
+		repos = append(repos, repo)
+	}
+	C.pkgcraft_array_free((*unsafe.Pointer)(unsafe.Pointer(c_repos)), length)
+	return repos
+}
+
+// Return the repos an ebuild repo inherits metadata from, i.e. its masters
+// followed by the repo itself which takes precedence.
+func (self *EbuildRepo) trees() []*EbuildRepo {
+	return append(self.Masters(), self)
+}
+
+// Return the non-empty, non-comment lines of a repo file, nonexistent files
+// have no lines.
+func (self *EbuildRepo) readLines(path string) ([]string, error) {
+	f, err := os.Open(filepath.Join(self.Path(), path))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// Return the lines of a repo file across an ebuild repo's inheritance chain,
+// ordered from its first master to the repo itself.
+func (self *EbuildRepo) readTreeLines(path string) ([]string, error) {
+	var lines []string
+	for _, repo := range self.trees() {
+		vals, err := repo.readLines(path)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, vals...)
+	}
+	return lines, nil
+}
+
+// Return the sorted, unique names of files with a given extension in a repo
+// directory across an ebuild repo's inheritance chain.
+func (self *EbuildRepo) readTreeDirNames(path, ext string) ([]string, error) {
+	seen := make(map[string]bool)
+	var names []string
+	for _, repo := range self.trees() {
+		entries, err := os.ReadDir(filepath.Join(repo.Path(), path))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			name := strings.TrimSuffix(entry.Name(), ext)
+			if !entry.IsDir() && strings.HasSuffix(entry.Name(), ext) && !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Return an ebuild repo's name from its profiles/repo_name file.
+func (self *EbuildRepo) RepoName() (string, error) {
+	lines, err := self.readLines("profiles/repo_name")
+	if err != nil {
+		return "", err
+	} else if len(lines) == 0 {
+		return "", fmt.Errorf("missing repo name: %s", self.Path())
+	}
+	return lines[0], nil
+}
+
+// Return the known arches from the profiles/arch.list files of an ebuild repo
+// and its masters.
+func (self *EbuildRepo) Arches() ([]string, error) {
+	lines, err := self.readTreeLines("profiles/arch.list")
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var arches []string
+	for _, arch := range lines {
+		if !seen[arch] {
+			seen[arch] = true
+			arches = append(arches, arch)
+		}
+	}
+	return arches, nil
+}
+
+// Return an ebuild repo's categories from its profiles/categories file.
+//
+// Note that this differs from Categories() which returns the categories
+// containing packages.
+func (self *EbuildRepo) ProfileCategories() ([]string, error) {
+	return self.readLines("profiles/categories")
+}
+
+// Return the sorted names of the licenses of an ebuild repo and its masters.
+func (self *EbuildRepo) Licenses() ([]string, error) {
+	return self.readTreeDirNames("licenses", "")
+}
+
+// Return the sorted names of the eclasses of an ebuild repo and its masters.
+func (self *EbuildRepo) Eclasses() ([]string, error) {
+	return self.readTreeDirNames("eclass", ".eclass")
+}
+
+// Return the license groups from the profiles/license_groups files of an
+// ebuild repo and its masters, with nested groups expanded into their
+// licenses. Groups defined by a repo override those of its masters.
+func (self *EbuildRepo) LicenseGroups() (map[string][]string, error) {
+	lines, err := self.readTreeLines("profiles/license_groups")
+	if err != nil {
+		return nil, err
+	}
+
+	raw := make(map[string][]string)
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		raw[fields[0]] = fields[1:]
+	}
+
+	groups := make(map[string][]string)
+	var expand func(name string, seen []string) ([]string, error)
+	expand = func(name string, seen []string) ([]string, error) {
+		if licenses, ok := groups[name]; ok {
+			return licenses, nil
+		}
+		for _, s := range seen {
+			if s == name {
+				return nil, fmt.Errorf("license group cycle: %s", strings.Join(append(seen, name), " -> "))
+			}
+		}
+		values, ok := raw[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown license group: %s", name)
+		}
+
+		var licenses []string
+		for _, value := range values {
+			if group, found := strings.CutPrefix(value, "@"); found {
+				vals, err := expand(group, append(seen, name))
+				if err != nil {
+					return nil, err
+				}
+				licenses = append(licenses, vals...)
+			} else {
+				licenses = append(licenses, value)
+			}
+		}
+		sort.Strings(licenses)
+		groups[name] = licenses
+		return licenses, nil
+	}
+
+	for name := range raw {
+		if _, err := expand(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return groups, nil
+}
+
+// Return the mirrors from the profiles/thirdpartymirrors files of an ebuild
+// repo and its masters. Mirrors defined by a repo override those of its
+// masters.
+func (self *EbuildRepo) Mirrors() (map[string][]string, error) {
+	lines, err := self.readTreeLines("profiles/thirdpartymirrors")
+	if err != nil {
+		return nil, err
+	}
+
+	mirrors := make(map[string][]string)
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("invalid thirdpartymirrors line: %q", line)
+		}
+		mirrors[fields[0]] = fields[1:]
+	}
+	return mirrors, nil
+}
+
+// Split a USE description line into its flag and description.
+func splitUseDesc(path, line string) (string, string, error) {
+	flag, desc, found := strings.Cut(line, " - ")
+	if !found {
+		return "", "", fmt.Errorf("invalid %s line: %q", filepath.Base(path), line)
+	}
+	return strings.TrimSpace(flag), strings.TrimSpace(desc), nil
+}
+
+// Return the global USE flag descriptions from the profiles/use.desc files of
+// an ebuild repo and its masters. Descriptions from a repo override those of
+// its masters.
+func (self *EbuildRepo) UseDescriptions() (map[string]string, error) {
+	path := "profiles/use.desc"
+	lines, err := self.readTreeLines(path)
+	if err != nil {
+		return nil, err
+	}
+
+	descs := make(map[string]string)
+	for _, line := range lines {
+		flag, desc, err := splitUseDesc(path, line)
+		if err != nil {
+			return nil, err
+		}
+		descs[flag] = desc
+	}
+	return descs, nil
+}
+
+// Return the package specific USE flag descriptions from the
+// profiles/use.local.desc files of an ebuild repo and its masters.
+// Descriptions from a repo override those of its masters.
+func (self *EbuildRepo) UseLocalDescriptions() (*CpnMap[map[string]string], error) {
+	path := "profiles/use.local.desc"
+	lines, err := self.readTreeLines(path)
+	if err != nil {
+		return nil, err
+	}
+
+	descs := NewCpnMap[map[string]string]()
+	for _, line := range lines {
+		s, desc, err := splitUseDesc(path, line)
+		if err != nil {
+			return nil, err
+		}
+		pkg, flag, found := strings.Cut(s, ":")
+		if !found {
+			return nil, fmt.Errorf("invalid use.local.desc line: %q", line)
+		}
+		cpn, err := NewCpn(pkg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid use.local.desc line: %q: %w", line, err)
+		}
+		flags, ok := descs.Get(cpn)
+		if !ok {
+			flags = make(map[string]string)
+			descs.Set(cpn, flags)
+		}
+		flags[flag] = desc
+	}
+	return descs, nil
+}
@@ -0,0 +1,284 @@
+//go:build cgo && !nocgo
+
+package pkgcraft_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/pkgcraft/pkgcraft-go"
+)
+
+// Create a temporary ebuild repo from a mapping of relative file paths to
+// content, returning its path.
+func createEbuildRepo(t *testing.T, files map[string]string) string {
+	path := t.TempDir()
+	for name, data := range files {
+		file := filepath.Join(path, name)
+		assert.Nil(t, os.MkdirAll(filepath.Dir(file), 0o755))
+		assert.Nil(t, os.WriteFile(file, []byte(data), 0o644))
+	}
+	return path
+}
+
+// Return the package from a temporary ebuild repo containing a single ebuild.
+func newEbuildPkg(t *testing.T, ebuild string) *EbuildPkg {
+	path := createEbuildRepo(t, map[string]string{
+		"profiles/repo_name":   "test\n",
+		"profiles/categories":  "cat\n",
+		"metadata/layout.conf": "masters =\n",
+		"cat/pkg/pkg-1.ebuild": ebuild,
+	})
+	config := NewConfig()
+	t.Cleanup(config.Close)
+	assert.Nil(t, config.AddRepoPath(path, "test", 0))
+	iter := config.ReposEbuild["test"].Iter()
+	if !iter.HasNext() {
+		t.Fatalf("failed loading ebuild: %s", ebuild)
+	}
+	return iter.Next()
+}
+
+// Return a temporary ebuild repo containing a broken and a valid ebuild.
+func newBrokenEbuildRepo(t *testing.T) *EbuildRepo {
+	path := createEbuildRepo(t, map[string]string{
+		"profiles/repo_name":   "test\n",
+		"profiles/categories":  "cat\n",
+		"metadata/layout.conf": "masters =\n",
+		"cat/pkg/pkg-1.ebuild": "EAPI=8\nDESCRIPTION=\"valid\"\nSLOT=0\n",
+		"cat/pkg/pkg-2.ebuild": "EAPI=nonexistent\nDESCRIPTION=\"broken\"\nSLOT=0\n",
+		"cat/pkg/pkg-3.ebuild": "EAPI=8\nDESCRIPTION=\"valid\"\nSLOT=0\n",
+	})
+	config := NewConfig()
+	t.Cleanup(config.Close)
+	assert.Nil(t, config.AddRepoPath(path, "test", 0))
+	return config.ReposEbuild["test"]
+}
+
+func TestEbuildRepoLoadErrors(t *testing.T) {
+	var loadErr *PkgLoadError
+	repo := newBrokenEbuildRepo(t)
+	restrict, _ := NewRestrict("cat/pkg")
+
+	// iterators continue past broken packages and report them
+	for _, iter := range []PkgIterator[*EbuildPkg]{repo.Iter(), repo.IterRestrict(restrict)} {
+		var cpvs []string
+		for iter.HasNext() {
+			cpvs = append(cpvs, iter.Next().Cpv().String())
+		}
+		assert.Equal(t, cpvs, []string{"cat/pkg-1", "cat/pkg-3"})
+		assert.True(t, errors.As(iter.Err(), &loadErr))
+		assert.Equal(t, len(loadErr.Errs), 1)
+		iter.Close()
+	}
+
+	// functions yield load errors
+	for _, seq := range []PkgSeq[*EbuildPkg]{repo.PkgsSeq(), repo.RestrictPkgsSeq(restrict)} {
+		var cpvs []string
+		var errs []error
+		seq(func(pkg *EbuildPkg, err error) bool {
+			if err != nil {
+				errs = append(errs, err)
+			} else {
+				cpvs = append(cpvs, pkg.Cpv().String())
+			}
+			return true
+		})
+		assert.Equal(t, cpvs, []string{"cat/pkg-1", "cat/pkg-3"})
+		assert.Equal(t, len(errs), 1)
+	}
+
+	// cancellable channels report load errors once closed
+	pkgs, errs := repo.PkgsContext(context.Background())
+	var cpvs []string
+	for pkg := range pkgs {
+		cpvs = append(cpvs, pkg.Cpv().String())
+	}
+	assert.Equal(t, cpvs, []string{"cat/pkg-1", "cat/pkg-3"})
+	assert.True(t, errors.As(errs(), &loadErr))
+	assert.Equal(t, len(loadErr.Errs), 1)
+	pkgs, errs = repo.RestrictPkgsContext(context.Background(), restrict)
+	for range pkgs {
+	}
+	assert.True(t, errors.As(errs(), &loadErr))
+
+	// plain channels are closed at the first load error
+	for _, pkgs := range []<-chan *EbuildPkg{repo.Pkgs(), repo.RestrictPkgs(restrict)} {
+		var cpvs []string
+		for pkg := range pkgs {
+			cpvs = append(cpvs, pkg.Cpv().String())
+		}
+		assert.Equal(t, cpvs, []string{"cat/pkg-1"})
+	}
+}
+
+func TestEbuildRepoMatchesLoadErrors(t *testing.T) {
+	var loadErr *PkgLoadError
+	repo := newBrokenEbuildRepo(t)
+	dep, _ := NewDep("cat/pkg")
+
+	// valid matches are returned alongside load errors
+	pkgs, err := repo.Matches(dep)
+	assert.True(t, errors.As(err, &loadErr))
+	assert.Equal(t, len(pkgs), 2)
+	assert.Equal(t, pkgs[0].Cpv().String(), "cat/pkg-1")
+	assert.Equal(t, pkgs[1].Cpv().String(), "cat/pkg-3")
+	pkg, err := repo.BestMatch(dep)
+	assert.True(t, errors.As(err, &loadErr))
+	assert.False(t, errors.Is(err, ErrPkgNotFound))
+	assert.Equal(t, pkg.Cpv().String(), "cat/pkg-3")
+	slots, err := repo.BestMatchBySlot(dep)
+	assert.True(t, errors.As(err, &loadErr))
+	assert.Equal(t, slots["0"].Cpv().String(), "cat/pkg-3")
+
+	// only broken packages match
+	dep, _ = NewDep("=cat/pkg-2")
+	pkgs, err = repo.Matches(dep)
+	assert.True(t, errors.As(err, &loadErr))
+	assert.Empty(t, pkgs)
+	_, err = repo.BestMatch(dep)
+	assert.True(t, errors.As(err, &loadErr))
+	assert.True(t, errors.Is(err, ErrPkgNotFound))
+}
+
+func TestEbuildRepoMetadata(t *testing.T) {
+	primary := createEbuildRepo(t, map[string]string{
+		"profiles/repo_name":         "primary\n",
+		"metadata/layout.conf":       "masters =\n",
+		"profiles/arch.list":         "# comment\namd64\n\narm64\n",
+		"profiles/categories":        "cat\nother\n",
+		"profiles/license_groups":    "FREE @OSI GPL-2\nOSI MIT BSD\n",
+		"profiles/thirdpartymirrors": "gentoo https://a/ https://b/\n",
+		"profiles/use.desc":          "# comment\nfoo - Enable foo\nbar - Enable bar - and more\n",
+		"profiles/use.local.desc":    "cat/pkg:baz - Enable baz\ncat/pkg:qux - Enable qux\n",
+		"licenses/MIT":               "",
+		"licenses/BSD":               "",
+		"eclass/b.eclass":            "",
+		"eclass/a.eclass":            "",
+		"eclass/README":              "",
+		"cat/pkg/pkg-1.ebuild":       "EAPI=8\nSLOT=0\n",
+	})
+	overlay := createEbuildRepo(t, map[string]string{
+		"profiles/repo_name":         "overlay\n",
+		"metadata/layout.conf":       "masters = primary\n",
+		"profiles/arch.list":         "arm64\nriscv\n",
+		"profiles/license_groups":    "OSI MIT\n",
+		"profiles/thirdpartymirrors": "other https://c/\n",
+		"profiles/use.desc":          "foo - Enable overlay foo\n",
+		"licenses/GPL-2":             "",
+		"eclass/a.eclass":            "",
+		"eclass/c.eclass":            "",
+	})
+
+	config := NewConfig()
+	defer config.Close()
+	assert.Nil(t, config.AddRepoPath(primary, "primary", 0))
+	assert.Nil(t, config.AddRepoPath(overlay, "overlay", 0))
+	repo := config.ReposEbuild["primary"]
+
+	// masters
+	assert.Empty(t, repo.Masters())
+	masters := config.ReposEbuild["overlay"].Masters()
+	assert.Equal(t, len(masters), 1)
+	assert.Equal(t, masters[0].Id(), "primary")
+
+	name, err := repo.RepoName()
+	assert.Nil(t, err)
+	assert.Equal(t, name, "primary")
+
+	arches, err := repo.Arches()
+	assert.Nil(t, err)
+	assert.Equal(t, arches, []string{"amd64", "arm64"})
+
+	categories, err := repo.ProfileCategories()
+	assert.Nil(t, err)
+	assert.Equal(t, categories, []string{"cat", "other"})
+
+	licenses, err := repo.Licenses()
+	assert.Nil(t, err)
+	assert.Equal(t, licenses, []string{"BSD", "MIT"})
+
+	groups, err := repo.LicenseGroups()
+	assert.Nil(t, err)
+	assert.Equal(t, groups["FREE"], []string{"BSD", "GPL-2", "MIT"})
+	assert.Equal(t, groups["OSI"], []string{"BSD", "MIT"})
+
+	mirrors, err := repo.Mirrors()
+	assert.Nil(t, err)
+	assert.Equal(t, mirrors["gentoo"], []string{"https://a/", "https://b/"})
+
+	descs, err := repo.UseDescriptions()
+	assert.Nil(t, err)
+	assert.Equal(t, descs, map[string]string{"foo": "Enable foo", "bar": "Enable bar - and more"})
+
+	local, err := repo.UseLocalDescriptions()
+	assert.Nil(t, err)
+	cpn, _ := NewCpn("cat/pkg")
+	flags, ok := local.Get(cpn)
+	assert.True(t, ok)
+	assert.Equal(t, flags, map[string]string{"baz": "Enable baz", "qux": "Enable qux"})
+
+	eclasses, err := repo.Eclasses()
+	assert.Nil(t, err)
+	assert.Equal(t, eclasses, []string{"a", "b"})
+
+	// metadata inherited from masters with the repo taking precedence
+	repo = config.ReposEbuild["overlay"]
+	arches, err = repo.Arches()
+	assert.Nil(t, err)
+	assert.Equal(t, arches, []string{"amd64", "arm64", "riscv"})
+	categories, err = repo.ProfileCategories()
+	assert.Nil(t, err)
+	assert.Empty(t, categories)
+	licenses, err = repo.Licenses()
+	assert.Nil(t, err)
+	assert.Equal(t, licenses, []string{"BSD", "GPL-2", "MIT"})
+	groups, err = repo.LicenseGroups()
+	assert.Nil(t, err)
+	assert.Equal(t, groups["FREE"], []string{"GPL-2", "MIT"})
+	assert.Equal(t, groups["OSI"], []string{"MIT"})
+	mirrors, err = repo.Mirrors()
+	assert.Nil(t, err)
+	assert.Equal(t, mirrors, map[string][]string{"gentoo": {"https://a/", "https://b/"}, "other": {"https://c/"}})
+	descs, err = repo.UseDescriptions()
+	assert.Nil(t, err)
+	assert.Equal(t, descs, map[string]string{"foo": "Enable overlay foo", "bar": "Enable bar - and more"})
+	local, err = repo.UseLocalDescriptions()
+	assert.Nil(t, err)
+	flags, ok = local.Get(cpn)
+	assert.True(t, ok)
+	assert.Equal(t, flags, map[string]string{"baz": "Enable baz", "qux": "Enable qux"})
+	eclasses, err = repo.Eclasses()
+	assert.Nil(t, err)
+	assert.Equal(t, eclasses, []string{"a", "b", "c"})
+}
+
+func TestEbuildRepoMetadataInvalid(t *testing.T) {
+	path := createEbuildRepo(t, map[string]string{
+		"profiles/repo_name":         "test\n",
+		"metadata/layout.conf":       "masters =\n",
+		"profiles/license_groups":    "A @B\nB @A\n",
+		"profiles/thirdpartymirrors": "gentoo\n",
+		"profiles/use.desc":          "foo\n",
+		"profiles/use.local.desc":    "foo - desc\n",
+	})
+
+	config := NewConfig()
+	defer config.Close()
+	assert.Nil(t, config.AddRepoPath(path, "test", 0))
+	repo := config.ReposEbuild["test"]
+
+	_, err := repo.LicenseGroups()
+	assert.NotNil(t, err)
+	_, err = repo.Mirrors()
+	assert.NotNil(t, err)
+	_, err = repo.UseDescriptions()
+	assert.NotNil(t, err)
+	_, err = repo.UseLocalDescriptions()
+	assert.NotNil(t, err)
+}
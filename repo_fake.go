@@ -1,3 +1,5 @@
+//go:build cgo && !nocgo
+
 package pkgcraft
 
 // #cgo pkg-config: pkgcraft
@@ -5,6 +7,7 @@ package pkgcraft
 import "C"
 
 import (
+	"context"
 	"runtime"
 	"unsafe"
 )
@@ -51,21 +54,75 @@ func (self *FakeRepo) createPkg(ptr *C.Pkg) *FakePkg {
 }
 
 // Return an iterator over the packages of a repo.
-func (self *FakeRepo) Iter() *repoIter[*FakePkg] {
+func (self *FakeRepo) Iter() PkgIterator[*FakePkg] {
 	return newRepoIter[*FakePkg](self)
 }
 
 // Return a channel iterating over the packages of a repo.
+//
+// The channel is closed at the first package that fails to load and must be
+// drained to release its resources, use PkgsContext() or PkgsSeq() to report
+// load errors or stop early.
 func (self *FakeRepo) Pkgs() <-chan *FakePkg {
-	return repoPkgs((pkgRepo[*FakePkg])(self))
+	return seqChan(self.PkgsSeq())
+}
+
+// Return a channel iterating over the packages of a repo that is closed when
+// the context is cancelled, along with a function returning a *PkgLoadError
+// for packages that failed to load which blocks until iteration ends.
+func (self *FakeRepo) PkgsContext(ctx context.Context) (<-chan *FakePkg, func() error) {
+	return seqChanContext(ctx, self.PkgsSeq())
+}
+
+// Return a function iterating over the packages of a repo along with their
+// load errors.
+func (self *FakeRepo) PkgsSeq() PkgSeq[*FakePkg] {
+	return repoPkgs[*FakePkg](self)
 }
 
 // Return an iterator over the restricted packages of a repo.
-func (self *FakeRepo) IterRestrict(restrict *Restrict) *repoIterRestrict[*FakePkg] {
+func (self *FakeRepo) IterRestrict(restrict *Restrict) PkgIterator[*FakePkg] {
 	return newRepoIterRestrict[*FakePkg](self, restrict)
 }
 
 // Return a channel iterating over the restricted packages of a repo.
+//
+// The channel is closed at the first package that fails to load and must be
+// drained to release its resources, use RestrictPkgsContext() or
+// RestrictPkgsSeq() to report load errors or stop early.
 func (self *FakeRepo) RestrictPkgs(restrict *Restrict) <-chan *FakePkg {
-	return repoRestrictPkgs((pkgRepo[*FakePkg])(self), restrict)
+	return seqChan(self.RestrictPkgsSeq(restrict))
+}
+
+// Return a channel iterating over the restricted packages of a repo that is
+// closed when the context is cancelled, along with a function returning a
+// *PkgLoadError for packages that failed to load which blocks until iteration
+// ends.
+func (self *FakeRepo) RestrictPkgsContext(ctx context.Context, restrict *Restrict) (<-chan *FakePkg, func() error) {
+	return seqChanContext(ctx, self.RestrictPkgsSeq(restrict))
+}
+
+// Return a function iterating over the restricted packages of a repo along
+// with their load errors.
+func (self *FakeRepo) RestrictPkgsSeq(restrict *Restrict) PkgSeq[*FakePkg] {
+	return repoRestrictPkgs[*FakePkg](self, restrict)
+}
+
+// Return the package matching a Cpv, returning an error wrapping
+// ErrPkgNotFound if it doesn't exist.
+func (self *FakeRepo) Get(cpv *Cpv) (*FakePkg, error) {
+	return repoGet[*FakePkg](self, cpv)
+}
+
+// Return the packages matching a dependency sorted by version, along with a
+// non-fatal *PkgLoadError for matching packages that failed to load.
+func (self *FakeRepo) Matches(dep *Dep) ([]*FakePkg, error) {
+	return repoMatches[*FakePkg](self, dep)
+}
+
+// Return the highest versioned package matching a dependency, returning an
+// error wrapping ErrPkgNotFound if no packages match. Matching packages that
+// failed to load are reported via a non-fatal *PkgLoadError.
+func (self *FakeRepo) BestMatch(dep *Dep) (*FakePkg, error) {
+	return repoBestMatch[*FakePkg](self, dep)
 }
@@ -1,7 +1,12 @@
+//go:build cgo && !nocgo
+
 package pkgcraft_test
 
 import (
+	"context"
+	"runtime"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -187,3 +192,165 @@ func TestFakeRepoRestrictPkgs(t *testing.T) {
 	}
 	assert.Equal(t, cpvs, []string{"cat/pkg-1"})
 }
+
+func TestFakeRepoPkgsContext(t *testing.T) {
+	repo, _ := NewFakeRepo("fake", 0, []string{"cat/pkg-1", "cat/pkg-2", "cat/pkg-3"})
+	restrict, _ := NewRestrict("<cat/pkg-3")
+
+	// full iteration
+	var cpvs []string
+	pkgs, errs := repo.PkgsContext(context.Background())
+	for pkg := range pkgs {
+		cpvs = append(cpvs, pkg.Cpv().String())
+	}
+	assert.Equal(t, cpvs, []string{"cat/pkg-1", "cat/pkg-2", "cat/pkg-3"})
+	assert.Nil(t, errs())
+
+	// cancelling ends iteration without draining the channel
+	goroutines := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	pkgs, errs = repo.PkgsContext(ctx)
+	pkg := <-pkgs
+	assert.Equal(t, pkg.Cpv().String(), "cat/pkg-1")
+	cancel()
+	assert.Nil(t, errs())
+	assert.Eventually(t, func() bool { return runtime.NumGoroutine() <= goroutines },
+		time.Second, 10*time.Millisecond)
+	for range pkgs {
+	}
+
+	// restricted
+	ctx, cancel = context.WithCancel(context.Background())
+	pkgs, _ = repo.RestrictPkgsContext(ctx, restrict)
+	pkg = <-pkgs
+	assert.Equal(t, pkg.Cpv().String(), "cat/pkg-1")
+	cancel()
+	for range pkgs {
+	}
+
+	// cancelled before iterating
+	pkgs, errs = repo.RestrictPkgsContext(ctx, restrict)
+	for range pkgs {
+	}
+	assert.Nil(t, errs())
+}
+
+func TestFakeRepoPkgsSeq(t *testing.T) {
+	var cpvs []string
+	repo, _ := NewFakeRepo("fake", 0, []string{"cat/pkg-1", "cat/pkg-2", "cat/pkg-3"})
+	restrict, _ := NewRestrict("<cat/pkg-3")
+
+	// full iteration
+	repo.PkgsSeq()(func(pkg *FakePkg, err error) bool {
+		assert.Nil(t, err)
+		cpvs = append(cpvs, pkg.Cpv().String())
+		return true
+	})
+	assert.Equal(t, cpvs, []string{"cat/pkg-1", "cat/pkg-2", "cat/pkg-3"})
+
+	// stopping early
+	cpvs = cpvs[:0]
+	repo.PkgsSeq()(func(pkg *FakePkg, err error) bool {
+		assert.Nil(t, err)
+		cpvs = append(cpvs, pkg.Cpv().String())
+		return false
+	})
+	assert.Equal(t, cpvs, []string{"cat/pkg-1"})
+
+	// restricted
+	cpvs = cpvs[:0]
+	repo.RestrictPkgsSeq(restrict)(func(pkg *FakePkg, err error) bool {
+		assert.Nil(t, err)
+		cpvs = append(cpvs, pkg.Cpv().String())
+		return true
+	})
+	assert.Equal(t, cpvs, []string{"cat/pkg-1", "cat/pkg-2"})
+
+	// contains doesn't require full iteration
+	assert.True(t, repo.Contains(restrict))
+	restrict, _ = NewRestrict("cat/pkg-4")
+	assert.False(t, repo.Contains(restrict))
+}
+
+func TestFakeRepoIterClose(t *testing.T) {
+	var iter PkgIterator[*FakePkg]
+	repo, _ := NewFakeRepo("fake", 0, []string{"cat/pkg-1", "cat/pkg-2"})
+	restrict, _ := NewRestrict("cat/pkg")
+
+	for _, iter = range []PkgIterator[*FakePkg]{repo.Iter(), repo.IterRestrict(restrict)} {
+		assert.True(t, iter.HasNext())
+		assert.Equal(t, iter.Next().Cpv().String(), "cat/pkg-1")
+		iter.Close()
+		assert.False(t, iter.HasNext())
+		assert.Nil(t, iter.Err())
+		// closing multiple times is allowed
+		iter.Close()
+	}
+}
+
+func TestFakeRepoListings(t *testing.T) {
+	// empty
+	repo, _ := NewFakeRepo("fake", 0, []string{})
+	assert.Empty(t, repo.Categories())
+	assert.Empty(t, repo.Packages("cat"))
+	assert.Empty(t, repo.Versions("cat", "pkg"))
+
+	// multiple pkgs
+	repo, _ = NewFakeRepo("fake", 0, []string{"cat2/a-1", "cat1/b-2", "cat1/b-1.0", "cat1/a-1"})
+	assert.Equal(t, repo.Categories(), []string{"cat1", "cat2"})
+	assert.Equal(t, repo.Packages("cat1"), []string{"a", "b"})
+	assert.Equal(t, repo.Packages("cat2"), []string{"a"})
+	assert.Empty(t, repo.Packages("cat3"))
+	var vers []string
+	for _, v := range repo.Versions("cat1", "b") {
+		vers = append(vers, v.String())
+	}
+	assert.Equal(t, vers, []string{"1.0", "2"})
+	assert.Empty(t, repo.Versions("cat1", "c"))
+}
+
+func TestFakeRepoGet(t *testing.T) {
+	repo, _ := NewFakeRepo("fake", 0, []string{"cat/pkg-1", "cat/pkg-2"})
+
+	cpv, _ := NewCpv("cat/pkg-2")
+	pkg, err := repo.Get(cpv)
+	assert.Nil(t, err)
+	assert.Equal(t, pkg.Cpv().String(), "cat/pkg-2")
+
+	// nonexistent
+	cpv, _ = NewCpv("cat/pkg-3")
+	pkg, err = repo.Get(cpv)
+	assert.Nil(t, pkg)
+	assert.ErrorIs(t, err, ErrPkgNotFound)
+}
+
+func TestFakeRepoMatches(t *testing.T) {
+	repo, _ := NewFakeRepo("fake", 0, []string{"cat/pkg-2", "cat/pkg-1", "cat/pkg-10", "a/b-1"})
+
+	// matching packages are sorted by version
+	dep, _ := NewDep(">=cat/pkg-2")
+	pkgs, err := repo.Matches(dep)
+	assert.Nil(t, err)
+	var cpvs []string
+	for _, pkg := range pkgs {
+		cpvs = append(cpvs, pkg.Cpv().String())
+	}
+	assert.Equal(t, cpvs, []string{"cat/pkg-2", "cat/pkg-10"})
+
+	// best match
+	dep, _ = NewDep("cat/pkg")
+	pkg, err := repo.BestMatch(dep)
+	assert.Nil(t, err)
+	assert.Equal(t, pkg.Cpv().String(), "cat/pkg-10")
+	dep, _ = NewDep("<cat/pkg-10")
+	pkg, _ = repo.BestMatch(dep)
+	assert.Equal(t, pkg.Cpv().String(), "cat/pkg-2")
+
+	// nonexistent
+	dep, _ = NewDep(">cat/pkg-10")
+	pkgs, err = repo.Matches(dep)
+	assert.Nil(t, err)
+	assert.Empty(t, pkgs)
+	_, err = repo.BestMatch(dep)
+	assert.ErrorIs(t, err, ErrPkgNotFound)
+}
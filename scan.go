@@ -0,0 +1,20 @@
+package pkgcraft
+
+import (
+	"encoding"
+	"fmt"
+)
+
+// Scan a database value into an object supporting text unmarshalling.
+func scanText(obj encoding.TextUnmarshaler, src interface{}) error {
+	switch src := src.(type) {
+	case string:
+		return obj.UnmarshalText([]byte(src))
+	case []byte:
+		return obj.UnmarshalText(src)
+	case nil:
+		return obj.UnmarshalText([]byte{})
+	default:
+		return fmt.Errorf("unsupported scan type for %T: %T", obj, src)
+	}
+}
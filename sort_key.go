@@ -0,0 +1,143 @@
+package pkgcraft
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var versionRe = regexp.MustCompile(
+	`^(<=|>=|<|=|~|>)?(\d+(?:\.\d+)*)([a-z])?((?:_(?:alpha|beta|pre|rc|p)\d*)*)(?:-r(\d+))?(\*)?$`)
+var versionSuffixRe = regexp.MustCompile(`_(alpha|beta|pre|rc|p)(\d*)`)
+
+// Version suffix kinds ordered by precedence with the end of the suffix list
+// positioned between release candidates and patch releases.
+var versionSuffixKinds = map[string]byte{
+	"alpha": 1,
+	"beta":  2,
+	"pre":   3,
+	"rc":    4,
+	"p":     6,
+}
+
+const versionSuffixEnd = 5
+
+type versionSuffix struct {
+	kind   string
+	number string
+}
+
+// Version components parsed natively in Go.
+type parsedVersion struct {
+	op       string
+	numbers  []string
+	letter   string
+	suffixes []versionSuffix
+	revision string
+	glob     bool
+}
+
+// Parse a version string into its components.
+func parseVersion(s string) (*parsedVersion, error) {
+	m := versionRe.FindStringSubmatch(s)
+	if m == nil {
+		return nil, fmt.Errorf("invalid version: %s", s)
+	}
+
+	ver := &parsedVersion{
+		op:       m[1],
+		numbers:  strings.Split(m[2], "."),
+		letter:   m[3],
+		revision: m[5],
+		glob:     m[6] != "",
+	}
+	for _, suffix := range versionSuffixRe.FindAllStringSubmatch(m[4], -1) {
+		ver.suffixes = append(ver.suffixes, versionSuffix{suffix[1], suffix[2]})
+	}
+	return ver, nil
+}
+
+// Append an integer string to a sort key, encoding its length to allow
+// bytewise comparisons.
+func appendIntKey(key []byte, s string) []byte {
+	s = strings.TrimLeft(s, "0")
+	key = append(key, byte(len(s)>>8), byte(len(s)))
+	return append(key, s...)
+}
+
+// Return a byte string that sorts identically to the version comparison
+// algorithm defined by PMS, ignoring operators.
+func (self *parsedVersion) sortKey() []byte {
+	// first version component is always compared numerically
+	key := appendIntKey(nil, self.numbers[0])
+
+	// Remaining components with leading zeros are compared as strings with
+	// trailing zeros stripped, otherwise numerically. Those with leading zeros
+	// always sort before those without so they're tagged accordingly.
+	for _, s := range self.numbers[1:] {
+		if strings.HasPrefix(s, "0") {
+			key = append(key, 1)
+			key = append(key, strings.TrimRight(s, "0")...)
+			key = append(key, 0)
+		} else {
+			key = append(key, 2)
+			key = appendIntKey(key, s)
+		}
+	}
+	key = append(key, 0)
+
+	// a missing letter sorts before all letters
+	if self.letter != "" {
+		key = append(key, self.letter[0])
+	} else {
+		key = append(key, 0)
+	}
+
+	for _, suffix := range self.suffixes {
+		key = append(key, versionSuffixKinds[suffix.kind])
+		key = appendIntKey(key, suffix.number)
+	}
+	key = append(key, versionSuffixEnd)
+
+	return appendIntKey(key, self.revision)
+}
+
+// Sort versions in place using precomputed sort keys, avoiding the overhead of
+// calling into C for every comparison.
+func SortVersions(versions []*Version) {
+	keys := make([]string, len(versions))
+	for i, v := range versions {
+		keys[i] = v.sortKey()
+	}
+	sort.Stable(keySorter[*Version]{keys, versions})
+}
+
+// Sort Cpvs in place using precomputed sort keys, avoiding the overhead of
+// calling into C for every comparison.
+func SortCpvs(cpvs []*Cpv) {
+	keys := make([]string, len(cpvs))
+	for i, cpv := range cpvs {
+		keys[i] = cpv.sortKey()
+	}
+	sort.Stable(keySorter[*Cpv]{keys, cpvs})
+}
+
+// Sort a slice of values using a related slice of sort keys.
+type keySorter[T any] struct {
+	keys []string
+	vals []T
+}
+
+func (self keySorter[T]) Len() int {
+	return len(self.keys)
+}
+
+func (self keySorter[T]) Less(i, j int) bool {
+	return self.keys[i] < self.keys[j]
+}
+
+func (self keySorter[T]) Swap(i, j int) {
+	self.keys[i], self.keys[j] = self.keys[j], self.keys[i]
+	self.vals[i], self.vals[j] = self.vals[j], self.vals[i]
+}
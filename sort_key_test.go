@@ -0,0 +1,100 @@
+package pkgcraft_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/pkgcraft/pkgcraft-go"
+	. "github.com/pkgcraft/pkgcraft-go/internal"
+)
+
+func TestVersionSortKey(t *testing.T) {
+	for _, s := range VERSION_TOML.Compares {
+		vals := strings.Fields(s)
+		v1, _ := NewVersion(vals[0])
+		op := vals[1]
+		v2, _ := NewVersion(vals[2])
+		result := bytes.Compare(v1.SortKey(), v2.SortKey())
+
+		switch op {
+		case "<":
+			assert.Equal(t, result, -1, "failed: %s", s)
+		case "==":
+			assert.Equal(t, result, 0, "failed: %s", s)
+		case "!=":
+			assert.NotEqual(t, result, 0, "failed: %s", s)
+		case ">":
+			assert.Equal(t, result, 1, "failed: %s", s)
+		default:
+			panic(fmt.Sprintf("invalid operator: %s", op))
+		}
+
+		// verify results match pkgcraft
+		assert.Equal(t, result, v1.Cmp(v2), "failed: %s", s)
+	}
+
+	// versions without values
+	assert.Empty(t, (&Version{}).SortKey())
+}
+
+func TestSortVersions(t *testing.T) {
+	for _, data := range VERSION_TOML.Sorting {
+		var expected []*Version
+		for _, s := range data.Sorted {
+			ver, _ := NewVersion(s)
+			expected = append(expected, ver)
+		}
+
+		sorted := make([]*Version, len(expected))
+		copy(sorted, expected)
+		ReverseSlice(sorted)
+		SortVersions(sorted)
+
+		// equal versions aren't sorted so reversing should restore the original order
+		if data.Equal {
+			ReverseSlice(sorted)
+		}
+
+		assert.Equal(t, len(sorted), len(expected))
+		for i := range sorted {
+			assert.True(t, sorted[i].Cmp(expected[i]) == 0, "%s != %s", sorted, expected)
+		}
+	}
+}
+
+func TestSortCpvs(t *testing.T) {
+	var cpvs []*Cpv
+	for _, s := range []string{"b/a-1", "a/b-2", "a/b-1.0", "a/b-1", "a/a-1", "a/b-1-r1", "a/b_c-1"} {
+		cpv, _ := NewCpv(s)
+		cpvs = append(cpvs, cpv)
+	}
+
+	SortCpvs(cpvs)
+	var sorted []string
+	for _, cpv := range cpvs {
+		sorted = append(sorted, cpv.String())
+	}
+	assert.Equal(t, sorted, []string{"a/a-1", "a/b-1", "a/b-1-r1", "a/b-1.0", "a/b-2", "a/b_c-1", "b/a-1"})
+
+	// verify results match pkgcraft
+	for i := range cpvs[1:] {
+		assert.Equal(t, cpvs[i].Cmp(cpvs[i+1]), bytes.Compare(cpvs[i].SortKey(), cpvs[i+1].SortKey()))
+	}
+}
+
+func BenchmarkSortVersions(b *testing.B) {
+	var versions []*Version
+	for i := 100; i > 0; i-- {
+		v, _ := NewVersion(fmt.Sprintf("%d", i))
+		versions = append(versions, v)
+	}
+	assert.Equal(b, versions[0].String(), "100")
+	for i := 0; i < b.N; i++ {
+		SortVersions(versions)
+	}
+	assert.Equal(b, versions[0].String(), "1")
+}
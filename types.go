@@ -0,0 +1,56 @@
+package pkgcraft
+
+type Blocker int
+
+const (
+	BlockerNone Blocker = iota
+	BlockerStrong
+	BlockerWeak
+)
+
+type SlotOperator int
+
+const (
+	SlotOpNone SlotOperator = iota
+	SlotOpEqual
+	SlotOpStar
+)
+
+type Operator int
+
+const (
+	OperatorNone Operator = iota
+	OperatorLess
+	OperatorLessOrEqual
+	OperatorEqual
+	OperatorEqualGlob
+	OperatorApproximate
+	OperatorGreaterOrEqual
+	OperatorGreater
+)
+
+func (self Operator) String() string {
+	switch self {
+	case OperatorLess:
+		return "<"
+	case OperatorLessOrEqual:
+		return "<="
+	case OperatorEqual:
+		return "="
+	case OperatorEqualGlob:
+		return "=*"
+	case OperatorApproximate:
+		return "~"
+	case OperatorGreaterOrEqual:
+		return ">="
+	case OperatorGreater:
+		return ">"
+	default:
+		return ""
+	}
+}
+
+type Pair[T, U any] struct {
+	First  T
+	Second U
+}
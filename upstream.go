@@ -0,0 +1,136 @@
+package pkgcraft
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Prefixes such as "v", "release-", or project names that can contain digits
+// followed by a separator, e.g. "gtk3-" or "python3.11-".
+var upstreamPrefixRe = regexp.MustCompile(`^(?:[a-z][a-z0-9.+_-]*?[-_])?v?\d`)
+var upstreamDateRe = regexp.MustCompile(`^((?:19|20)\d{2})[-_](0[1-9]|1[0-2])[-_](0[1-9]|[12]\d|3[01])`)
+var upstreamNumbersRe = regexp.MustCompile(`^\d+(?:\.\d+)*`)
+var upstreamSepNumbersRe = regexp.MustCompile(`^\d+(?:[-_]\d+)*`)
+var upstreamSuffixRe = regexp.MustCompile(
+	`^[._-]?(alpha|beta|preview|pre|rc|cr|patch|post|pl|p|dev|a|b|c)[._-]?(\d*)`)
+var upstreamPatchRe = regexp.MustCompile(`^[._-](\d+)`)
+
+// Mapping of upstream release suffixes to version suffixes.
+var upstreamSuffixes = map[string]string{
+	"alpha":   "alpha",
+	"a":       "alpha",
+	"beta":    "beta",
+	"b":       "beta",
+	"preview": "pre",
+	"pre":     "pre",
+	"dev":     "pre",
+	"rc":      "rc",
+	"c":       "rc",
+	"cr":      "rc",
+	"patch":   "p",
+	"post":    "p",
+	"pl":      "p",
+	"p":       "p",
+}
+
+// Version converted from an upstream release string.
+type UpstreamVersion struct {
+	Version *Version
+	// parts of the upstream string were discarded, e.g. build metadata
+	Lossy bool
+	// parts of the upstream string have multiple plausible interpretations
+	Ambiguous bool
+	// descriptions of lossy or ambiguous conversions
+	Notes []string
+}
+
+func (self *UpstreamVersion) lossy(format string, a ...any) {
+	self.Lossy = true
+	self.Notes = append(self.Notes, fmt.Sprintf(format, a...))
+}
+
+func (self *UpstreamVersion) ambiguous(format string, a ...any) {
+	self.Ambiguous = true
+	self.Notes = append(self.Notes, fmt.Sprintf(format, a...))
+}
+
+// Convert an upstream release string into a version.
+//
+// Common release formats are supported including prefixed tags such as
+// "v1.2.3", "release-1_2_3", or "gtk3-3.24.1", separated release suffixes such
+// as "1.2.3-rc1" or "1.2.3.beta2", and dates such as "2023-09-15" which are
+// converted to "20230915". Unrecognized trailing parts, e.g. build metadata,
+// are dropped and flagged as lossy while conversions that could be interpreted
+// in multiple ways are flagged as ambiguous.
+func NewUpstreamVersion(s string) (*UpstreamVersion, error) {
+	result := &UpstreamVersion{}
+	orig := s
+	s = strings.ToLower(strings.TrimSpace(s))
+
+	// strip prefixes such as "v" or "release-"
+	if m := upstreamPrefixRe.FindString(s); m != "" {
+		s = s[len(m)-1:]
+	} else {
+		return nil, fmt.Errorf("invalid upstream version: %s", orig)
+	}
+
+	var b strings.Builder
+	if m := upstreamDateRe.FindStringSubmatch(s); m != nil {
+		b.WriteString(m[1] + m[2] + m[3])
+		s = s[len(m[0]):]
+	} else {
+		// components separated by underscores or hyphens, e.g. "1_2_3", are
+		// only supported if no periods are used
+		m := upstreamNumbersRe.FindString(s)
+		if !strings.Contains(m, ".") {
+			m = upstreamSepNumbersRe.FindString(s)
+		}
+		numbers := strings.FieldsFunc(m, func(r rune) bool { return strings.ContainsRune("._-", r) })
+		for i, n := range numbers {
+			if i > 0 && strings.HasPrefix(n, "0") && len(n) > 1 {
+				result.ambiguous("component %q with leading zeros is compared as a string", n)
+			}
+		}
+		b.WriteString(strings.Join(numbers, "."))
+		s = s[len(m):]
+	}
+
+	// single letter suffix, e.g. "1.2b"
+	if len(s) > 0 && s[0] >= 'a' && s[0] <= 'z' && (len(s) == 1 || !isAlphanumeric(s[1])) {
+		b.WriteByte(s[0])
+		s = s[1:]
+	}
+
+	for len(s) > 0 {
+		if m := upstreamSuffixRe.FindStringSubmatch(s); m != nil && (len(s) == len(m[0]) || !isLetter(s[len(m[0])])) {
+			if m[1] == "dev" {
+				result.ambiguous("development release %q treated as a pre-release", m[0])
+			}
+			b.WriteString("_" + upstreamSuffixes[m[1]] + m[2])
+			s = s[len(m[0]):]
+		} else if m := upstreamPatchRe.FindStringSubmatch(s); m != nil {
+			result.ambiguous("trailing number %q treated as a patch release", m[0])
+			b.WriteString("_p" + m[1])
+			s = s[len(m[0]):]
+		} else {
+			result.lossy("dropped %q", s)
+			break
+		}
+	}
+
+	ver, err := NewVersion(b.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid upstream version: %s: %w", orig, err)
+	}
+	result.Version = ver
+	return result, nil
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z'
+}
+
+func isAlphanumeric(c byte) bool {
+	return isLetter(c) || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,74 @@
+package pkgcraft_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/pkgcraft/pkgcraft-go"
+)
+
+func TestNewUpstreamVersion(t *testing.T) {
+	// exact conversions
+	for s, expected := range map[string]string{
+		"1.2.3":              "1.2.3",
+		"v1.2.3":             "1.2.3",
+		"V1.2":               "1.2",
+		"release-1_2_3":      "1.2.3",
+		"pkg-1.2.3":          "1.2.3",
+		"release-v1.2":       "1.2",
+		"gtk3-3.24.1":        "3.24.1",
+		"libfoo2-1.2.3":      "1.2.3",
+		"python3.11-3.11.4":  "3.11.4",
+		"foo-bar-1.2":        "1.2",
+		"1.2.3-rc1":          "1.2.3_rc1",
+		"1.2.3.beta2":        "1.2.3_beta2",
+		"1.2.3-alpha.1":      "1.2.3_alpha1",
+		"1.0a1":              "1.0_alpha1",
+		"1.2RC":              "1.2_rc",
+		"1.2-preview3":       "1.2_pre3",
+		"1.2.post1":          "1.2_p1",
+		"1.2b":               "1.2b",
+		"1.2b-rc1":           "1.2b_rc1",
+		"2023-09-15":         "20230915",
+		"nightly_2023_09_15": "20230915",
+		"2023.09.15":         "2023.09.15",
+	} {
+		ver, err := NewUpstreamVersion(s)
+		assert.Nil(t, err, "%s failed", s)
+		assert.Equal(t, ver.Version.String(), expected, "%s failed", s)
+		assert.False(t, ver.Lossy, "%s failed", s)
+	}
+
+	// lossy conversions
+	for s, expected := range map[string]string{
+		"1.2.3+build5":                  "1.2.3",
+		"v2.0.0-beta.2+exp.sha.5114f85": "2.0.0_beta2",
+		"1.0-final":                     "1.0",
+	} {
+		ver, err := NewUpstreamVersion(s)
+		assert.Nil(t, err, "%s failed", s)
+		assert.Equal(t, ver.Version.String(), expected, "%s failed", s)
+		assert.True(t, ver.Lossy, "%s failed", s)
+		assert.NotEmpty(t, ver.Notes, "%s failed", s)
+	}
+
+	// ambiguous conversions
+	for s, expected := range map[string]string{
+		"1.2.3-1":      "1.2.3_p1",
+		"1.2.dev4":     "1.2_pre4",
+		"2023.09.15-2": "2023.09.15_p2",
+	} {
+		ver, err := NewUpstreamVersion(s)
+		assert.Nil(t, err, "%s failed", s)
+		assert.Equal(t, ver.Version.String(), expected, "%s failed", s)
+		assert.True(t, ver.Ambiguous, "%s failed", s)
+		assert.False(t, ver.Lossy, "%s failed", s)
+	}
+
+	// invalid
+	for _, s := range []string{"", "latest", "v", "gtk3-latest", "gtk3.24"} {
+		_, err := NewUpstreamVersion(s)
+		assert.NotNil(t, err, "%s didn't fail", s)
+	}
+}
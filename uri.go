@@ -0,0 +1,80 @@
+//go:build cgo && !nocgo
+
+package pkgcraft
+
+// #cgo pkg-config: pkgcraft
+// #include <pkgcraft.h>
+import "C"
+
+import (
+	"runtime"
+	"strings"
+)
+
+type UriPrefix int
+
+const (
+	UriPrefixNone UriPrefix = iota
+	UriPrefixFetch
+	UriPrefixMirror
+)
+
+type Uri struct {
+	ptr *C.Uri
+	// cached fields
+	_uri string
+}
+
+func uriFromPtr(ptr *C.Uri) *Uri {
+	uri := &Uri{ptr: ptr}
+	runtime.SetFinalizer(uri, func(self *Uri) { C.pkgcraft_uri_free(self.ptr) })
+	return uri
+}
+
+// Return a URI's full target, including any fetch or mirror prefix.
+func (self *Uri) Uri() string {
+	if self._uri == "" {
+		s := C.pkgcraft_uri_uri(self.ptr)
+		defer C.pkgcraft_str_free(s)
+		self._uri = C.GoString(s)
+	}
+	return self._uri
+}
+
+// Return a URI's distfile name, using the rename target if one exists.
+func (self *Uri) Filename() string {
+	s := C.pkgcraft_uri_filename(self.ptr)
+	defer C.pkgcraft_str_free(s)
+	return C.GoString(s)
+}
+
+// Return a URI's restriction override prefix, i.e. "fetch+" or "mirror+",
+// supported by EAPI 8 and up.
+func (self *Uri) Prefix() UriPrefix {
+	uri := self.Uri()
+	if strings.HasPrefix(uri, "fetch+") {
+		return UriPrefixFetch
+	} else if strings.HasPrefix(uri, "mirror+") {
+		return UriPrefixMirror
+	}
+	return UriPrefixNone
+}
+
+// Return a URI's target with any fetch or mirror prefix removed.
+func (self *Uri) Url() string {
+	uri := self.Uri()
+	switch self.Prefix() {
+	case UriPrefixFetch:
+		return strings.TrimPrefix(uri, "fetch+")
+	case UriPrefixMirror:
+		return strings.TrimPrefix(uri, "mirror+")
+	default:
+		return uri
+	}
+}
+
+func (self *Uri) String() string {
+	s := C.pkgcraft_uri_str(self.ptr)
+	defer C.pkgcraft_str_free(s)
+	return C.GoString(s)
+}
@@ -0,0 +1,43 @@
+//go:build cgo && !nocgo
+
+package pkgcraft_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/pkgcraft/pkgcraft-go"
+)
+
+func TestUri(t *testing.T) {
+	set, err := NewDependencySet(
+		"https://a.com/b.tar.gz https://a.com/c.tar.gz -> d.tar.gz fetch+https://a.com/e.tar.gz mirror+mirror://a/f.tar.gz -> g.tar.gz",
+		DependencyUnitUri, EAPI_LATEST_OFFICIAL)
+	assert.Nil(t, err)
+	uris := set.Uris()
+	assert.Equal(t, len(uris), 4)
+
+	// plain
+	assert.Equal(t, uris[0].Uri(), "https://a.com/b.tar.gz")
+	assert.Equal(t, uris[0].Url(), "https://a.com/b.tar.gz")
+	assert.Equal(t, uris[0].Filename(), "b.tar.gz")
+	assert.Equal(t, uris[0].Prefix(), UriPrefixNone)
+	assert.Equal(t, uris[0].String(), "https://a.com/b.tar.gz")
+
+	// renamed
+	assert.Equal(t, uris[1].Uri(), "https://a.com/c.tar.gz")
+	assert.Equal(t, uris[1].Filename(), "d.tar.gz")
+	assert.Equal(t, uris[1].String(), "https://a.com/c.tar.gz -> d.tar.gz")
+
+	// fetch prefix
+	assert.Equal(t, uris[2].Uri(), "fetch+https://a.com/e.tar.gz")
+	assert.Equal(t, uris[2].Url(), "https://a.com/e.tar.gz")
+	assert.Equal(t, uris[2].Filename(), "e.tar.gz")
+	assert.Equal(t, uris[2].Prefix(), UriPrefixFetch)
+
+	// mirror prefix with rename
+	assert.Equal(t, uris[3].Url(), "mirror://a/f.tar.gz")
+	assert.Equal(t, uris[3].Filename(), "g.tar.gz")
+	assert.Equal(t, uris[3].Prefix(), UriPrefixMirror)
+}
@@ -0,0 +1,177 @@
+package pkgcraft
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+type UseDepKind int
+
+const (
+	UseDepEnabled             UseDepKind = iota // flag
+	UseDepDisabled                              // -flag
+	UseDepEqual                                 // flag=
+	UseDepNotEqual                              // !flag=
+	UseDepEnabledConditional                    // flag?
+	UseDepDisabledConditional                   // !flag?
+)
+
+type UseDepDefault int
+
+const (
+	UseDepDefaultNone     UseDepDefault = iota
+	UseDepDefaultEnabled                // (+)
+	UseDepDefaultDisabled               // (-)
+)
+
+var useFlagRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9+_@-]*$`)
+
+type UseDep struct {
+	kind     UseDepKind
+	flag     string
+	_default UseDepDefault
+}
+
+// Parse a string into a USE dependency, e.g. "!flag(+)=".
+func NewUseDep(s string) (*UseDep, error) {
+	var kind UseDepKind
+	flag := s
+
+	switch {
+	case strings.HasSuffix(flag, "?"):
+		flag = strings.TrimSuffix(flag, "?")
+		kind = UseDepEnabledConditional
+		if strings.HasPrefix(flag, "!") {
+			flag = flag[1:]
+			kind = UseDepDisabledConditional
+		}
+	case strings.HasSuffix(flag, "="):
+		flag = strings.TrimSuffix(flag, "=")
+		kind = UseDepEqual
+		if strings.HasPrefix(flag, "!") {
+			flag = flag[1:]
+			kind = UseDepNotEqual
+		}
+	case strings.HasPrefix(flag, "-"):
+		flag = flag[1:]
+		kind = UseDepDisabled
+	default:
+		kind = UseDepEnabled
+	}
+
+	var _default UseDepDefault
+	if strings.HasSuffix(flag, "(+)") {
+		flag = strings.TrimSuffix(flag, "(+)")
+		_default = UseDepDefaultEnabled
+	} else if strings.HasSuffix(flag, "(-)") {
+		flag = strings.TrimSuffix(flag, "(-)")
+		_default = UseDepDefaultDisabled
+	}
+
+	if !useFlagRe.MatchString(flag) {
+		return nil, fmt.Errorf("invalid USE dependency: %s", s)
+	}
+
+	return &UseDep{kind, flag, _default}, nil
+}
+
+// Return a USE dependency's kind.
+func (self *UseDep) Kind() UseDepKind {
+	return self.kind
+}
+
+// Return a USE dependency's flag name.
+func (self *UseDep) Flag() string {
+	return self.flag
+}
+
+// Return a USE dependency's default for packages missing the flag in IUSE.
+func (self *UseDep) Default() UseDepDefault {
+	return self._default
+}
+
+// Return true if a USE dependency requires its flag to be enabled, either
+// unconditionally or when its condition is met.
+func (self *UseDep) Enabled() bool {
+	switch self.kind {
+	case UseDepEnabled, UseDepEqual, UseDepEnabledConditional:
+		return true
+	default:
+		return false
+	}
+}
+
+// Return true if a USE dependency is conditional, e.g. "flag?" or "!flag?".
+func (self *UseDep) Conditional() bool {
+	return self.kind == UseDepEnabledConditional || self.kind == UseDepDisabledConditional
+}
+
+// Return true if a USE dependency is an equality, e.g. "flag=" or "!flag=".
+func (self *UseDep) Equality() bool {
+	return self.kind == UseDepEqual || self.kind == UseDepNotEqual
+}
+
+// Determine if a USE dependency is satisfied by a package's enabled USE flags
+// and IUSE. The parent's enabled USE flags are used to resolve conditional and
+// equality variants.
+func (self *UseDep) Satisfied(parent, enabled, iuse []string) bool {
+	var state bool
+	if slices.Contains(iuse, self.flag) {
+		state = slices.Contains(enabled, self.flag)
+	} else {
+		switch self._default {
+		case UseDepDefaultEnabled:
+			state = true
+		case UseDepDefaultDisabled:
+			state = false
+		default:
+			return false
+		}
+	}
+
+	parent_state := slices.Contains(parent, self.flag)
+	switch self.kind {
+	case UseDepEnabled:
+		return state
+	case UseDepDisabled:
+		return !state
+	case UseDepEqual:
+		return state == parent_state
+	case UseDepNotEqual:
+		return state != parent_state
+	case UseDepEnabledConditional:
+		return !parent_state || state
+	case UseDepDisabledConditional:
+		return parent_state || !state
+	default:
+		return false
+	}
+}
+
+func (self *UseDep) String() string {
+	flag := self.flag
+	switch self._default {
+	case UseDepDefaultEnabled:
+		flag += "(+)"
+	case UseDepDefaultDisabled:
+		flag += "(-)"
+	}
+
+	switch self.kind {
+	case UseDepDisabled:
+		return "-" + flag
+	case UseDepEqual:
+		return flag + "="
+	case UseDepNotEqual:
+		return "!" + flag + "="
+	case UseDepEnabledConditional:
+		return flag + "?"
+	case UseDepDisabledConditional:
+		return "!" + flag + "?"
+	default:
+		return flag
+	}
+}
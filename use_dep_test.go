@@ -0,0 +1,105 @@
+package pkgcraft_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/pkgcraft/pkgcraft-go"
+)
+
+func TestNewUseDep(t *testing.T) {
+	valid := []struct {
+		s        string
+		kind     UseDepKind
+		flag     string
+		_default UseDepDefault
+	}{
+		{"a", UseDepEnabled, "a", UseDepDefaultNone},
+		{"-a", UseDepDisabled, "a", UseDepDefaultNone},
+		{"a=", UseDepEqual, "a", UseDepDefaultNone},
+		{"!a=", UseDepNotEqual, "a", UseDepDefaultNone},
+		{"a?", UseDepEnabledConditional, "a", UseDepDefaultNone},
+		{"!a?", UseDepDisabledConditional, "a", UseDepDefaultNone},
+		{"a(+)", UseDepEnabled, "a", UseDepDefaultEnabled},
+		{"-a(-)", UseDepDisabled, "a", UseDepDefaultDisabled},
+		{"!a(+)=", UseDepNotEqual, "a", UseDepDefaultEnabled},
+		{"a-b_c+d@e(-)?", UseDepEnabledConditional, "a-b_c+d@e", UseDepDefaultDisabled},
+	}
+	for _, el := range valid {
+		use_dep, err := NewUseDep(el.s)
+		assert.Nil(t, err)
+		assert.Equal(t, use_dep.Kind(), el.kind, "unequal kind: %s", el.s)
+		assert.Equal(t, use_dep.Flag(), el.flag, "unequal flag: %s", el.s)
+		assert.Equal(t, use_dep.Default(), el._default, "unequal default: %s", el.s)
+		assert.Equal(t, use_dep.String(), el.s)
+	}
+
+	invalid := []string{"", "-", "!a", "-a?", "-a=", "a(+", "a(*)", "+a", "a?=", "a b"}
+	for _, s := range invalid {
+		_, err := NewUseDep(s)
+		assert.NotNil(t, err, "%s didn't fail", s)
+	}
+}
+
+func TestUseDepAttrs(t *testing.T) {
+	u, _ := NewUseDep("a")
+	assert.True(t, u.Enabled())
+	assert.False(t, u.Conditional())
+	assert.False(t, u.Equality())
+
+	u, _ = NewUseDep("!a?")
+	assert.False(t, u.Enabled())
+	assert.True(t, u.Conditional())
+	assert.False(t, u.Equality())
+
+	u, _ = NewUseDep("a=")
+	assert.True(t, u.Enabled())
+	assert.False(t, u.Conditional())
+	assert.True(t, u.Equality())
+}
+
+func TestUseDepSatisfied(t *testing.T) {
+	iuse := []string{"a", "b"}
+	enabled := []string{"a"}
+	tests := []struct {
+		s         string
+		parent    []string
+		satisfied bool
+	}{
+		{"a", nil, true},
+		{"b", nil, false},
+		{"-b", nil, true},
+		{"a=", []string{"a"}, true},
+		{"a=", nil, false},
+		{"!b=", []string{"b"}, true},
+		{"b?", []string{"b"}, false},
+		{"b?", nil, true},
+		{"!a?", nil, false},
+		{"!a?", []string{"a"}, true},
+		// flags missing from IUSE
+		{"c", nil, false},
+		{"c(+)", nil, true},
+		{"c(-)", nil, false},
+		{"-c(-)", nil, true},
+	}
+	for _, el := range tests {
+		u, _ := NewUseDep(el.s)
+		assert.Equal(t, u.Satisfied(el.parent, enabled, iuse), el.satisfied, "failed: %s", el.s)
+	}
+}
+
+func TestDepUseDeps(t *testing.T) {
+	dep, _ := NewDep("cat/pkg[a,-b(+),c?,!d=]")
+	var use []string
+	for _, u := range dep.UseDeps() {
+		use = append(use, u.String())
+	}
+	assert.Equal(t, use, dep.Use())
+	assert.Equal(t, dep.UseDeps()[1].Kind(), UseDepDisabled)
+	assert.Equal(t, dep.UseDeps()[1].Default(), UseDepDefaultEnabled)
+
+	// no USE deps
+	dep, _ = NewDep("cat/pkg")
+	assert.Empty(t, dep.UseDeps())
+}
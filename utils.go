@@ -1,3 +1,5 @@
+//go:build cgo && !nocgo
+
 package pkgcraft
 
 // #cgo pkg-config: pkgcraft
@@ -18,6 +20,14 @@ func sliceToCharArray(vals []string) (**C.char, C.size_t) {
 	return (**C.char)(c_strs), C.size_t(len(vals))
 }
 
+// Free an array of C strings allocated via sliceToCharArray().
+func freeCharArray(ptr **C.char, length C.size_t) {
+	for _, s := range unsafe.Slice(ptr, length) {
+		C.free(unsafe.Pointer(s))
+	}
+	C.free(unsafe.Pointer(ptr))
+}
+
 // Convert an array of C strings to a slice of Go strings.
 func charArrayToSlice(ptr **C.char, length C.size_t) []string {
 	slice := unsafe.Slice(ptr, length)
@@ -28,3 +38,13 @@ func charArrayToSlice(ptr **C.char, length C.size_t) []string {
 	C.pkgcraft_str_array_free(ptr, length)
 	return vals
 }
+
+// Populate an object in place from a parsed object owning a C pointer.
+//
+// The object can be part of a larger allocation, e.g. a struct field, so it
+// can't have a finalizer and instead references the parsed object that owns
+// the C pointer via its owner field.
+func populate[T any](self, obj *T, owner **T) {
+	*self = *obj
+	*owner = obj
+}
@@ -0,0 +1,36 @@
+//go:build !cgo || nocgo
+
+package pkgcraft
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+)
+
+// Return a new error in the same form as those returned by pkgcraft.
+func newParseError(format string, a ...any) error {
+	return &PkgcraftError{fmt.Sprintf(format, a...)}
+}
+
+func cmpInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func cmpStrings(a, b string) int {
+	return strings.Compare(a, b)
+}
+
+// Return the hash of a string.
+func hashString(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return h.Sum64()
+}
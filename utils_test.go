@@ -0,0 +1,74 @@
+package pkgcraft_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/pkgcraft/pkgcraft-go"
+)
+
+// Objects populated in place can be part of a larger allocation, e.g. struct
+// fields that aren't first or slice elements.
+func TestPopulateInPlace(t *testing.T) {
+	var row struct {
+		ID      int
+		Cpn     Cpn
+		Cpv     Cpv
+		Dep     Dep
+		Version Version
+	}
+	for _, tt := range []struct {
+		obj interface {
+			sql.Scanner
+			fmt.Stringer
+		}
+		value string
+	}{
+		{&row.Cpn, "cat/pkg"},
+		{&row.Cpv, "cat/pkg-1-r2"},
+		{&row.Dep, "=cat/pkg-1-r2:3/4=::repo[a,b,c]"},
+		{&row.Version, ">=1.2-r3"},
+	} {
+		// scanning
+		assert.Nil(t, tt.obj.Scan(tt.value))
+		assert.Nil(t, tt.obj.Scan([]byte(tt.value)))
+		runtime.GC()
+		assert.Equal(t, tt.obj.String(), tt.value)
+
+		// JSON
+		data, _ := json.Marshal(tt.value)
+		assert.Nil(t, json.Unmarshal(data, tt.obj))
+		runtime.GC()
+		assert.Equal(t, tt.obj.String(), tt.value)
+	}
+
+	var values struct {
+		Cpns     []Cpn
+		Cpvs     []Cpv
+		Deps     []Dep
+		Versions []Version
+	}
+	data := `{
+		"Cpns": ["a/b", "cat/pkg"],
+		"Cpvs": ["a/b-1", "cat/pkg-1-r2"],
+		"Deps": ["a/b", {"dep": "=cat/pkg-1", "eapi": "8"}],
+		"Versions": ["1", ">=1.2-r3"]
+	}`
+	assert.Nil(t, json.Unmarshal([]byte(data), &values))
+	runtime.GC()
+	var strs []string
+	for _, obj := range []fmt.Stringer{
+		&values.Cpns[0], &values.Cpns[1],
+		&values.Cpvs[0], &values.Cpvs[1],
+		&values.Deps[0], &values.Deps[1],
+		&values.Versions[0], &values.Versions[1],
+	} {
+		strs = append(strs, obj.String())
+	}
+	assert.Equal(t, strs, []string{"a/b", "cat/pkg", "a/b-1", "cat/pkg-1-r2", "a/b", "=cat/pkg-1", "1", ">=1.2-r3"})
+}
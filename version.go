@@ -1,3 +1,5 @@
+//go:build cgo && !nocgo
+
 package pkgcraft
 
 // #cgo pkg-config: pkgcraft
@@ -5,12 +7,16 @@ package pkgcraft
 import "C"
 
 import (
+	"database/sql/driver"
+	"fmt"
 	"runtime"
 	"unsafe"
 )
 
 type Revision struct {
 	ptr *C.Revision
+	// owner of the C pointer for objects populated in place
+	_owner *Revision
 }
 
 type revisionPtr interface {
@@ -19,8 +25,8 @@ type revisionPtr interface {
 
 func revisionFromPtr(ptr *C.Revision) (*Revision, error) {
 	if ptr != nil {
-		rev := &Revision{ptr}
-		runtime.SetFinalizer(rev, func(self *Revision) { C.pkgcraft_revision_free(self.ptr) })
+		rev := &Revision{ptr: ptr}
+		runtime.SetFinalizer(rev, func(*Revision) { C.pkgcraft_revision_free(ptr) })
 		return rev, nil
 	} else {
 		return nil, newPkgcraftError()
@@ -51,14 +57,64 @@ func (self *Revision) String() string {
 	return C.GoString(s)
 }
 
+func (self *Revision) MarshalText() ([]byte, error) {
+	if self == nil || self.ptr == nil {
+		return []byte{}, nil
+	}
+	return []byte(self.String()), nil
+}
+
+// Unmarshal a revision from text, an empty value resets it to the zero value.
+func (self *Revision) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		*self = Revision{}
+		return nil
+	}
+
+	rev, err := NewRevision(string(data))
+	if err != nil {
+		return err
+	}
+
+	populate(self, rev, &self._owner)
+	return nil
+}
+
+// Implement the sql.Scanner interface, NULL values are scanned as the zero value.
+func (self *Revision) Scan(src interface{}) error {
+	return scanText(self, src)
+}
+
+// Implement the driver.Valuer interface, the zero value is stored as NULL.
+func (self *Revision) Value() (driver.Value, error) {
+	if self == nil || self.ptr == nil {
+		return nil, nil
+	}
+	return self.String(), nil
+}
+
 func (self *Revision) Hash() uint64 {
 	return uint64(C.pkgcraft_revision_hash(self.ptr))
 }
 
+func OperatorFromString(s string) (Operator, error) {
+	c_str := C.CString(s)
+	i := C.pkgcraft_version_op_from_str(c_str)
+	C.free(unsafe.Pointer(c_str))
+	if i > 0 {
+		return Operator(i), nil
+	} else {
+		return OperatorNone, fmt.Errorf("invalid operator: %s", s)
+	}
+}
+
 type Version struct {
 	ptr *C.Version
 	// cached fields
 	_revision *Revision
+	_key      string
+	// owner of the C pointer for objects populated in place
+	_owner *Version
 }
 
 type versionPtr interface {
@@ -68,7 +124,7 @@ type versionPtr interface {
 func versionFromPtr(ptr *C.Version) (*Version, error) {
 	if ptr != nil {
 		ver := &Version{ptr: ptr}
-		runtime.SetFinalizer(ver, func(self *Version) { C.pkgcraft_version_free(self.ptr) })
+		runtime.SetFinalizer(ver, func(*Version) { C.pkgcraft_version_free(ptr) })
 		return ver, nil
 	} else {
 		return nil, newPkgcraftError()
@@ -83,6 +139,14 @@ func NewVersion(s string) (*Version, error) {
 	return versionFromPtr(ptr)
 }
 
+// Parse a string into a version with an operator, e.g. ">=1.2".
+func NewVersionWithOp(s string) (*Version, error) {
+	ver_str := C.CString(s)
+	defer C.free(unsafe.Pointer(ver_str))
+	ptr := C.pkgcraft_version_with_op(ver_str)
+	return versionFromPtr(ptr)
+}
+
 func (self *Version) p() *C.Version {
 	return self.ptr
 }
@@ -100,6 +164,14 @@ func (self *Version) Revision() *Revision {
 	return self._revision
 }
 
+// Return a version's operator.
+func (self *Version) Op() Operator {
+	if self.ptr == nil {
+		return OperatorNone
+	}
+	return Operator(C.pkgcraft_version_op(self.ptr))
+}
+
 // Compare a version with another version returning -1, 0, or 1 if the first is
 // less than, equal to, or greater than the second, respectively.
 func (self *Version) Cmp(other versionPtr) int {
@@ -112,10 +184,69 @@ func (self *Version) String() string {
 	return C.GoString(s)
 }
 
+func (self *Version) MarshalText() ([]byte, error) {
+	if self == nil || self.ptr == nil {
+		return []byte{}, nil
+	}
+	return []byte(self.String()), nil
+}
+
+// Unmarshal a version from text, an empty value resets it to the zero value.
+func (self *Version) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		*self = Version{}
+		return nil
+	}
+
+	ver, err := NewVersion(string(data))
+	if err != nil {
+		return err
+	}
+
+	populate(self, ver, &self._owner)
+	return nil
+}
+
+// Implement the sql.Scanner interface, NULL values are scanned as the zero value.
+func (self *Version) Scan(src interface{}) error {
+	return scanText(self, src)
+}
+
+// Implement the driver.Valuer interface, the zero value is stored as NULL.
+func (self *Version) Value() (driver.Value, error) {
+	if self == nil || self.ptr == nil {
+		return nil, nil
+	}
+	return self.String(), nil
+}
+
 func (self *Version) Hash() uint64 {
 	return uint64(C.pkgcraft_version_hash(self.ptr))
 }
 
+// Return a byte string that sorts bytewise identically to Cmp(), ignoring
+// operators. This allows sorting large numbers of versions natively in Go.
+func (self *Version) SortKey() []byte {
+	return []byte(self.sortKey())
+}
+
+func (self *Version) sortKey() string {
+	if self._key == "" && self.ptr != nil {
+		self._key = string(self.parsed().sortKey())
+	}
+	return self._key
+}
+
+// Return a version's components parsed natively in Go.
+func (self *Version) parsed() *parsedVersion {
+	if self.ptr == nil {
+		return nil
+	}
+	// versions are validated by pkgcraft so parsing can't fail
+	ver, _ := parseVersion(self.String())
+	return ver
+}
+
 // Determine if two versions intersect.
 func (self *Version) Intersects(other versionPtr) bool {
 	return bool(C.pkgcraft_version_intersects(self.ptr, other.p()))
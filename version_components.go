@@ -0,0 +1,183 @@
+package pkgcraft
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type VersionSuffixKind int
+
+const (
+	VersionSuffixAlpha VersionSuffixKind = iota + 1
+	VersionSuffixBeta
+	VersionSuffixPre
+	VersionSuffixRc
+	VersionSuffixP
+)
+
+var versionSuffixNames = map[VersionSuffixKind]string{
+	VersionSuffixAlpha: "alpha",
+	VersionSuffixBeta:  "beta",
+	VersionSuffixPre:   "pre",
+	VersionSuffixRc:    "rc",
+	VersionSuffixP:     "p",
+}
+
+var versionSuffixKindsByName = map[string]VersionSuffixKind{
+	"alpha": VersionSuffixAlpha,
+	"beta":  VersionSuffixBeta,
+	"pre":   VersionSuffixPre,
+	"rc":    VersionSuffixRc,
+	"p":     VersionSuffixP,
+}
+
+func (self VersionSuffixKind) String() string {
+	return versionSuffixNames[self]
+}
+
+// Release suffix of a version, e.g. "_rc2".
+type VersionSuffix struct {
+	Kind VersionSuffixKind
+	// suffix number as specified, empty if missing
+	Number string
+}
+
+func (self VersionSuffix) String() string {
+	return "_" + self.Kind.String() + self.Number
+}
+
+// Render version components back into a string.
+func (self *parsedVersion) String() string {
+	var b strings.Builder
+	b.WriteString(self.op)
+	b.WriteString(strings.Join(self.numbers, "."))
+	b.WriteString(self.letter)
+	for _, suffix := range self.suffixes {
+		b.WriteString("_" + suffix.kind + suffix.number)
+	}
+	if self.revision != "" {
+		b.WriteString("-r" + self.revision)
+	}
+	if self.glob {
+		b.WriteString("*")
+	}
+	return b.String()
+}
+
+// Increment an integer string, retaining any zero padding.
+func incrementNumber(s string) (string, error) {
+	if s == "" {
+		s = "0"
+	}
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil || n == ^uint64(0) {
+		return "", fmt.Errorf("number overflow: %s", s)
+	}
+	return fmt.Sprintf("%0*d", len(s), n+1), nil
+}
+
+// Return a new version with the given modifications applied to its components.
+func (self *Version) modify(f func(ver *parsedVersion) error) (*Version, error) {
+	ver := self.parsed()
+	if ver == nil {
+		return nil, fmt.Errorf("empty version")
+	}
+	if err := f(ver); err != nil {
+		return nil, fmt.Errorf("invalid version: %s: %s", self, err)
+	}
+	return NewVersion(ver.String())
+}
+
+// Return a version's numeric components, e.g. ["1", "02", "3"] for "1.02.3".
+//
+// Components are returned as specified since leading zeros affect comparisons.
+func (self *Version) Numbers() []string {
+	if ver := self.parsed(); ver != nil {
+		return ver.numbers
+	}
+	return nil
+}
+
+// Return a version's letter suffix, e.g. "b" for "1.2b", or an empty string.
+func (self *Version) Letter() string {
+	if ver := self.parsed(); ver != nil {
+		return ver.letter
+	}
+	return ""
+}
+
+// Return a version's release suffixes, e.g. "_beta2" and "_p1" for "1_beta2_p1".
+func (self *Version) Suffixes() []VersionSuffix {
+	var suffixes []VersionSuffix
+	if ver := self.parsed(); ver != nil {
+		for _, suffix := range ver.suffixes {
+			kind := versionSuffixKindsByName[suffix.kind]
+			suffixes = append(suffixes, VersionSuffix{kind, suffix.number})
+		}
+	}
+	return suffixes
+}
+
+// Return a new version with the numeric component at the given index
+// incremented and all following components, suffixes, and the revision
+// dropped, e.g. bumping index 1 of "1.2.3_rc1-r2" results in "1.3".
+//
+// Missing components are added as zeros when bumping past the last component,
+// e.g. bumping index 2 of "1" results in "1.0.1".
+func (self *Version) BumpComponent(idx int) (*Version, error) {
+	return self.modify(func(ver *parsedVersion) error {
+		if idx < 0 {
+			return fmt.Errorf("invalid component index: %d", idx)
+		}
+		for len(ver.numbers) <= idx {
+			ver.numbers = append(ver.numbers, "0")
+		}
+		n, err := incrementNumber(ver.numbers[idx])
+		if err != nil {
+			return err
+		}
+		ver.numbers = append(ver.numbers[:idx:idx], n)
+		ver.letter = ""
+		ver.suffixes = nil
+		ver.revision = ""
+		return nil
+	})
+}
+
+// Return a new version with the major component incremented, e.g. "1.2" -> "2".
+func (self *Version) BumpMajor() (*Version, error) {
+	return self.BumpComponent(0)
+}
+
+// Return a new version with the minor component incremented, e.g. "1.2.3" -> "1.3".
+func (self *Version) BumpMinor() (*Version, error) {
+	return self.BumpComponent(1)
+}
+
+// Return a new version with the revision incremented, e.g. "1.2" -> "1.2-r1".
+func (self *Version) BumpRevision() (*Version, error) {
+	return self.modify(func(ver *parsedVersion) error {
+		rev, err := incrementNumber(ver.revision)
+		ver.revision = rev
+		return err
+	})
+}
+
+// Return a new version without release suffixes or revision, e.g.
+// "1.2_rc3-r1" -> "1.2".
+func (self *Version) DropSuffixes() (*Version, error) {
+	return self.modify(func(ver *parsedVersion) error {
+		ver.suffixes = nil
+		ver.revision = ""
+		return nil
+	})
+}
+
+// Return a new version without its revision, e.g. "1.2-r3" -> "1.2".
+func (self *Version) DropRevision() (*Version, error) {
+	return self.modify(func(ver *parsedVersion) error {
+		ver.revision = ""
+		return nil
+	})
+}
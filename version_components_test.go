@@ -0,0 +1,85 @@
+package pkgcraft_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/pkgcraft/pkgcraft-go"
+)
+
+func TestVersionComponents(t *testing.T) {
+	ver, _ := NewVersion("1.02.3b_beta2_p-r4")
+	assert.Equal(t, ver.Numbers(), []string{"1", "02", "3"})
+	assert.Equal(t, ver.Letter(), "b")
+	suffixes := ver.Suffixes()
+	assert.Equal(t, suffixes, []VersionSuffix{{VersionSuffixBeta, "2"}, {VersionSuffixP, ""}})
+	assert.Equal(t, suffixes[0].String(), "_beta2")
+	assert.Equal(t, suffixes[1].String(), "_p")
+
+	// without optional components
+	ver, _ = NewVersion(">=1")
+	assert.Equal(t, ver.Numbers(), []string{"1"})
+	assert.Equal(t, ver.Letter(), "")
+	assert.Nil(t, ver.Suffixes())
+
+	// empty versions
+	assert.Nil(t, (&Version{}).Numbers())
+	assert.Equal(t, (&Version{}).Letter(), "")
+	assert.Nil(t, (&Version{}).Suffixes())
+}
+
+func TestVersionBump(t *testing.T) {
+	ver, _ := NewVersion("1.2.3_rc1-r2")
+
+	// components
+	for idx, expected := range []string{"2", "1.3", "1.2.4", "1.2.3.1", "1.2.3.0.1"} {
+		bumped, err := ver.BumpComponent(idx)
+		assert.Nil(t, err)
+		assert.Equal(t, bumped.String(), expected)
+	}
+	bumped, _ := ver.BumpMajor()
+	assert.Equal(t, bumped.String(), "2")
+	bumped, _ = ver.BumpMinor()
+	assert.Equal(t, bumped.String(), "1.3")
+
+	// zero padding is retained
+	ver, _ = NewVersion("2023.09")
+	bumped, _ = ver.BumpMinor()
+	assert.Equal(t, bumped.String(), "2023.10")
+
+	// revisions
+	ver, _ = NewVersion("1.2")
+	bumped, _ = ver.BumpRevision()
+	assert.Equal(t, bumped.String(), "1.2-r1")
+	bumped, _ = bumped.BumpRevision()
+	assert.Equal(t, bumped.String(), "1.2-r2")
+
+	// operators are retained
+	ver, _ = NewVersion(">=1.2")
+	bumped, _ = ver.BumpMinor()
+	assert.Equal(t, bumped.String(), ">=1.3")
+	assert.Equal(t, bumped.Op(), OperatorGreaterOrEqual)
+
+	// dropping components
+	ver, _ = NewVersion("1.2b_rc3_p1-r1")
+	bumped, _ = ver.DropSuffixes()
+	assert.Equal(t, bumped.String(), "1.2b")
+	bumped, _ = ver.DropRevision()
+	assert.Equal(t, bumped.String(), "1.2b_rc3_p1")
+
+	// original is unmodified
+	assert.Equal(t, ver.String(), "1.2b_rc3_p1-r1")
+
+	// invalid
+	_, err := ver.BumpComponent(-1)
+	assert.NotNil(t, err)
+	_, err = (&Version{}).BumpMajor()
+	assert.NotNil(t, err)
+	ver, _ = NewVersion("18446744073709551615")
+	_, err = ver.BumpMajor()
+	assert.NotNil(t, err)
+	ver, _ = NewVersion("~1")
+	_, err = ver.BumpRevision()
+	assert.NotNil(t, err)
+}
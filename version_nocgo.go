@@ -0,0 +1,449 @@
+//go:build !cgo || nocgo
+
+package pkgcraft
+
+import (
+	"database/sql/driver"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+var revisionRe = regexp.MustCompile(`^\d+$`)
+
+// Verify an integer string fits within the range supported by pkgcraft.
+func validNumber(s string) bool {
+	if s == "" {
+		return true
+	}
+	_, err := strconv.ParseUint(s, 10, 64)
+	return err == nil
+}
+
+// Compare two integer strings numerically.
+func cmpIntStrings(a, b string) int {
+	a = strings.TrimLeft(a, "0")
+	b = strings.TrimLeft(b, "0")
+	if c := cmpInts(len(a), len(b)); c != 0 {
+		return c
+	}
+	return cmpStrings(a, b)
+}
+
+type Revision struct {
+	value string
+}
+
+type revisionPtr interface {
+	p() *Revision
+}
+
+// Parse a string into a revision.
+func NewRevision(s string) (*Revision, error) {
+	if !revisionRe.MatchString(s) || !validNumber(s) {
+		return nil, newParseError("invalid revision: %s", s)
+	}
+	return &Revision{s}, nil
+}
+
+func (self *Revision) p() *Revision {
+	return self
+}
+
+// Compare a revision with another revision returning -1, 0, or 1 if the first
+// is less than, equal to, or greater than the second, respectively.
+func (self *Revision) Cmp(other revisionPtr) int {
+	return cmpIntStrings(self.value, other.p().value)
+}
+
+func (self *Revision) String() string {
+	return self.value
+}
+
+func (self *Revision) MarshalText() ([]byte, error) {
+	if self == nil {
+		return []byte{}, nil
+	}
+	return []byte(self.value), nil
+}
+
+// Unmarshal a revision from text, an empty value resets it to the zero value.
+func (self *Revision) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		*self = Revision{}
+		return nil
+	}
+	rev, err := NewRevision(string(data))
+	if err != nil {
+		return err
+	}
+	*self = *rev
+	return nil
+}
+
+// Implement the sql.Scanner interface, NULL values are scanned as the zero value.
+func (self *Revision) Scan(src interface{}) error {
+	return scanText(self, src)
+}
+
+// Implement the driver.Valuer interface, the zero value is stored as NULL.
+func (self *Revision) Value() (driver.Value, error) {
+	if self == nil || self.value == "" {
+		return nil, nil
+	}
+	return self.String(), nil
+}
+
+func (self *Revision) Hash() uint64 {
+	return hashString(strings.TrimLeft(self.value, "0"))
+}
+
+func OperatorFromString(s string) (Operator, error) {
+	for op := OperatorLess; op <= OperatorGreater; op++ {
+		if op.String() == s {
+			return op, nil
+		}
+	}
+	return OperatorNone, newParseError("invalid operator: %s", s)
+}
+
+type Version struct {
+	op Operator
+	// components excluding the operator and glob
+	version parsedVersion
+	// cached fields
+	_key string
+}
+
+type versionPtr interface {
+	p() *Version
+}
+
+// Parse a version string, optionally requiring an operator.
+func parseVersionWithOp(s string, required bool) (*Version, error) {
+	parsed, err := parseVersion(s)
+	if err != nil {
+		return nil, newParseError("invalid version: %s", s)
+	}
+
+	var op Operator
+	if parsed.op != "" {
+		op, _ = OperatorFromString(parsed.op)
+	}
+
+	switch {
+	case required && op == OperatorNone:
+		return nil, newParseError("invalid version: %s: missing operator", s)
+	case parsed.glob && op != OperatorEqual:
+		return nil, newParseError("invalid version: %s: glob requires the '=' operator", s)
+	case op == OperatorApproximate && parsed.revision != "":
+		return nil, newParseError("invalid version: %s: '~' operator can't have a revision", s)
+	case !validNumber(parsed.revision):
+		return nil, newParseError("invalid version: %s: revision overflow", s)
+	}
+	for _, n := range parsed.numbers {
+		if !validNumber(n) {
+			return nil, newParseError("invalid version: %s: component overflow", s)
+		}
+	}
+	for _, suffix := range parsed.suffixes {
+		if !validNumber(suffix.number) {
+			return nil, newParseError("invalid version: %s: suffix overflow", s)
+		}
+	}
+
+	if parsed.glob {
+		op = OperatorEqualGlob
+	}
+	parsed.op = ""
+	parsed.glob = false
+	return &Version{op: op, version: *parsed}, nil
+}
+
+// Parse a string into a version.
+func NewVersion(s string) (*Version, error) {
+	return parseVersionWithOp(s, false)
+}
+
+// Parse a string into a version with an operator, e.g. ">=1.2".
+func NewVersionWithOp(s string) (*Version, error) {
+	return parseVersionWithOp(s, true)
+}
+
+func (self *Version) p() *Version {
+	return self
+}
+
+// Return a version's revision.
+func (self *Version) Revision() *Revision {
+	return &Revision{self.version.revision}
+}
+
+// Return a version's operator.
+func (self *Version) Op() Operator {
+	return self.op
+}
+
+// Return a version without its operator.
+func (self *Version) withoutOp() *Version {
+	return &Version{version: self.version}
+}
+
+// Return a version's string without its operator or glob, optionally including
+// its revision.
+func (self *Version) base(revision bool) string {
+	ver := self.version
+	if !revision {
+		ver.revision = ""
+	}
+	return ver.String()
+}
+
+// Return a version's string without its operator, retaining any glob.
+func (self *Version) unprefixed() string {
+	if self.op == OperatorEqualGlob {
+		return self.base(true) + "*"
+	}
+	return self.base(true)
+}
+
+// Compare a version with another version returning -1, 0, or 1 if the first is
+// less than, equal to, or greater than the second, respectively.
+func (self *Version) Cmp(other versionPtr) int {
+	o := other.p()
+	if c := cmpStrings(self.sortKey(), o.sortKey()); c != 0 {
+		return c
+	}
+	return cmpInts(int(self.op), int(o.op))
+}
+
+func (self *Version) String() string {
+	switch self.op {
+	case OperatorNone:
+		return self.unprefixed()
+	case OperatorEqualGlob:
+		return "=" + self.unprefixed()
+	default:
+		return self.op.String() + self.unprefixed()
+	}
+}
+
+func (self *Version) MarshalText() ([]byte, error) {
+	if self == nil || self.version.numbers == nil {
+		return []byte{}, nil
+	}
+	return []byte(self.String()), nil
+}
+
+// Unmarshal a version from text, an empty value resets it to the zero value.
+func (self *Version) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		*self = Version{}
+		return nil
+	}
+	ver, err := NewVersion(string(data))
+	if err != nil {
+		return err
+	}
+	*self = *ver
+	return nil
+}
+
+// Implement the sql.Scanner interface, NULL values are scanned as the zero value.
+func (self *Version) Scan(src interface{}) error {
+	return scanText(self, src)
+}
+
+// Implement the driver.Valuer interface, the zero value is stored as NULL.
+func (self *Version) Value() (driver.Value, error) {
+	if self == nil || self.version.numbers == nil {
+		return nil, nil
+	}
+	return self.String(), nil
+}
+
+func (self *Version) Hash() uint64 {
+	return hashString(self.sortKey() + self.op.String())
+}
+
+// Return a byte string that sorts bytewise identically to Cmp(), ignoring
+// operators. This allows sorting large numbers of versions natively in Go.
+func (self *Version) SortKey() []byte {
+	return []byte(self.sortKey())
+}
+
+func (self *Version) sortKey() string {
+	if self._key == "" && self.version.numbers != nil {
+		self._key = string(self.version.sortKey())
+	}
+	return self._key
+}
+
+// Return a version's components.
+func (self *Version) parsed() *parsedVersion {
+	if self.version.numbers == nil {
+		return nil
+	}
+	ver := self.version
+	ver.numbers = slices.Clone(ver.numbers)
+	ver.suffixes = slices.Clone(ver.suffixes)
+	if self.op == OperatorEqualGlob {
+		ver.op = "="
+		ver.glob = true
+	} else {
+		ver.op = self.op.String()
+	}
+	return &ver
+}
+
+// Return a version's components normalized for equality comparisons.
+func (self *Version) components() []string {
+	comps := []string{strings.TrimLeft(self.version.numbers[0], "0")}
+	for _, s := range self.version.numbers[1:] {
+		if strings.HasPrefix(s, "0") {
+			comps = append(comps, "0"+strings.TrimRight(s, "0"))
+		} else {
+			comps = append(comps, s)
+		}
+	}
+	return comps
+}
+
+// Determine if a version matches a glob version, e.g. "=1.2*" matches "1.2"
+// and "1.2.3" but not "1.20".
+func (self *Version) globMatches(other *Version) bool {
+	glob := self.version
+	ver := other.version
+	comps, other_comps := self.components(), other.components()
+	if len(comps) > len(other_comps) {
+		return false
+	}
+	for i, s := range comps {
+		if s != other_comps[i] {
+			return false
+		}
+	}
+
+	// remaining components must match exactly when specified
+	if glob.letter == "" && glob.suffixes == nil && glob.revision == "" {
+		return true
+	}
+	if len(comps) != len(other_comps) || glob.letter != ver.letter {
+		return false
+	}
+	if len(glob.suffixes) > len(ver.suffixes) {
+		return false
+	}
+	for i, suffix := range glob.suffixes {
+		if suffix.kind != ver.suffixes[i].kind || cmpIntStrings(suffix.number, ver.suffixes[i].number) != 0 {
+			return false
+		}
+	}
+	if glob.revision == "" {
+		return true
+	}
+	return len(glob.suffixes) == len(ver.suffixes) && cmpIntStrings(glob.revision, ver.revision) == 0
+}
+
+// Determine if a version restriction contains a given version, operators on
+// the given version are ignored.
+func (self *Version) contains(other *Version) bool {
+	c := cmpStrings(other.sortKey(), self.sortKey())
+	switch self.op {
+	case OperatorLess:
+		return c < 0
+	case OperatorLessOrEqual:
+		return c <= 0
+	case OperatorApproximate:
+		return other.withoutRevision().sortKey() == self.sortKey()
+	case OperatorEqualGlob:
+		return self.globMatches(other)
+	case OperatorGreaterOrEqual:
+		return c >= 0
+	case OperatorGreater:
+		return c > 0
+	default:
+		return c == 0
+	}
+}
+
+// Return a version without its operator or revision.
+func (self *Version) withoutRevision() *Version {
+	ver := self.version
+	ver.revision = ""
+	return &Version{version: ver}
+}
+
+// Determine if two versions intersect.
+func (self *Version) Intersects(other versionPtr) bool {
+	o := other.p()
+	switch {
+	case self.op == OperatorNone || self.op == OperatorEqual:
+		return o.contains(self)
+	case o.op == OperatorNone || o.op == OperatorEqual:
+		return self.contains(o)
+	case self.op == OperatorApproximate:
+		return self.approximateIntersects(o)
+	case o.op == OperatorApproximate:
+		return o.approximateIntersects(self)
+	case self.op == OperatorEqualGlob:
+		return self.globIntersects(o)
+	case o.op == OperatorEqualGlob:
+		return o.globIntersects(self)
+	default:
+		return self.rangeIntersects(o)
+	}
+}
+
+// Determine if an approximate version intersects a ranged or glob version.
+func (self *Version) approximateIntersects(other *Version) bool {
+	switch other.op {
+	case OperatorApproximate:
+		return self.sortKey() == other.sortKey()
+	case OperatorEqualGlob:
+		return other.globMatches(self)
+	case OperatorLess, OperatorLessOrEqual:
+		return other.contains(self)
+	default:
+		return cmpStrings(self.sortKey(), other.withoutRevision().sortKey()) >= 0
+	}
+}
+
+// Determine if a glob version intersects a ranged version.
+func (self *Version) globIntersects(other *Version) bool {
+	if other.op == OperatorEqualGlob {
+		return self.globMatches(other) || other.globMatches(self)
+	}
+	return other.contains(self) || self.globMatches(other)
+}
+
+// Determine if two ranged versions intersect.
+func (self *Version) rangeIntersects(other *Version) bool {
+	upper, lower := self, other
+	if self.op == OperatorGreater || self.op == OperatorGreaterOrEqual {
+		upper, lower = other, self
+	}
+
+	// ranges heading in the same direction always intersect
+	if upper.op == OperatorGreater || upper.op == OperatorGreaterOrEqual {
+		return true
+	} else if lower.op == OperatorLess || lower.op == OperatorLessOrEqual {
+		return true
+	}
+
+	c := cmpStrings(upper.sortKey(), lower.sortKey())
+	if upper.op == OperatorLessOrEqual && lower.op == OperatorGreaterOrEqual {
+		return c >= 0
+	} else if upper.op == OperatorLess && lower.op == OperatorGreater {
+		// versions solely differing by consecutive revisions have no versions
+		// between them
+		if upper.withoutRevision().sortKey() == lower.withoutRevision().sortKey() {
+			u, _ := strconv.ParseUint("0"+upper.version.revision, 10, 64)
+			l, _ := strconv.ParseUint("0"+lower.version.revision, 10, 64)
+			return u > l+1
+		}
+	}
+	return c > 0
+}
@@ -0,0 +1,366 @@
+package pkgcraft
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Maximum revision supported by pkgcraft, used to bound approximate versions.
+const maxRevision = "18446744073709551615"
+
+// Version interval bound, unbounded if the version is nil.
+type versionBound struct {
+	version   *Version
+	inclusive bool
+}
+
+// Compare two lower bounds, unbounded values sort first.
+func cmpLowerBounds(a, b versionBound) int {
+	switch {
+	case a.version == nil && b.version == nil:
+		return 0
+	case a.version == nil:
+		return -1
+	case b.version == nil:
+		return 1
+	}
+	if c := strings.Compare(a.version.sortKey(), b.version.sortKey()); c != 0 {
+		return c
+	}
+	switch {
+	case a.inclusive == b.inclusive:
+		return 0
+	case a.inclusive:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// Compare two upper bounds, unbounded values sort last.
+func cmpUpperBounds(a, b versionBound) int {
+	switch {
+	case a.version == nil && b.version == nil:
+		return 0
+	case a.version == nil:
+		return 1
+	case b.version == nil:
+		return -1
+	}
+	if c := strings.Compare(a.version.sortKey(), b.version.sortKey()); c != 0 {
+		return c
+	}
+	switch {
+	case a.inclusive == b.inclusive:
+		return 0
+	case a.inclusive:
+		return 1
+	default:
+		return -1
+	}
+}
+
+// Continuous interval of versions.
+type versionInterval struct {
+	lower versionBound
+	upper versionBound
+}
+
+// Determine if an upper bound reaches a lower bound, i.e. no versions exist
+// between them.
+func boundsTouch(upper, lower versionBound) bool {
+	if upper.version == nil || lower.version == nil {
+		return true
+	}
+	c := strings.Compare(upper.version.sortKey(), lower.version.sortKey())
+	return c > 0 || (c == 0 && (upper.inclusive || lower.inclusive))
+}
+
+func (self versionInterval) empty() bool {
+	if self.lower.version == nil || self.upper.version == nil {
+		return false
+	}
+	c := strings.Compare(self.lower.version.sortKey(), self.upper.version.sortKey())
+	return c > 0 || (c == 0 && !(self.lower.inclusive && self.upper.inclusive))
+}
+
+func (self versionInterval) contains(v *Version) bool {
+	key := v.sortKey()
+	if self.lower.version != nil {
+		c := strings.Compare(key, self.lower.version.sortKey())
+		if c < 0 || (c == 0 && !self.lower.inclusive) {
+			return false
+		}
+	}
+	if self.upper.version != nil {
+		c := strings.Compare(key, self.upper.version.sortKey())
+		if c > 0 || (c == 0 && !self.upper.inclusive) {
+			return false
+		}
+	}
+	return true
+}
+
+// Return the version of an approximate interval, e.g. "1.2" for "~1.2" which
+// covers all revisions of the version.
+func (self versionInterval) approximate() *Version {
+	lower, upper := self.lower.version, self.upper.version
+	if lower == nil || upper == nil || !self.lower.inclusive || !self.upper.inclusive {
+		return nil
+	}
+	l, u := lower.parsed(), upper.parsed()
+	if l.revision != "" || u.revision != maxRevision {
+		return nil
+	}
+	u.revision = ""
+	if l.String() != u.String() {
+		return nil
+	}
+	return lower
+}
+
+// Return the version operator strings describing an interval.
+func (self versionInterval) strings() []string {
+	lower, upper := self.lower, self.upper
+	switch {
+	case lower.version == nil && upper.version == nil:
+		return nil
+	case lower.version != nil && upper.version != nil && lower.version.sortKey() == upper.version.sortKey():
+		return []string{"=" + lower.version.String()}
+	}
+	if v := self.approximate(); v != nil {
+		return []string{"~" + v.String()}
+	}
+
+	var vals []string
+	if lower.version != nil {
+		if lower.inclusive {
+			vals = append(vals, ">="+lower.version.String())
+		} else {
+			vals = append(vals, ">"+lower.version.String())
+		}
+	}
+	if upper.version != nil {
+		if upper.inclusive {
+			vals = append(vals, "<="+upper.version.String())
+		} else {
+			vals = append(vals, "<"+upper.version.String())
+		}
+	}
+	return vals
+}
+
+// Set of versions composed of disjoint intervals.
+type VersionRange struct {
+	intervals []versionInterval
+}
+
+// Return a new version from components without an operator.
+func versionFromParsed(ver *parsedVersion) *Version {
+	ver.op = ""
+	ver.glob = false
+	// components originate from valid versions so parsing can't fail
+	v, _ := NewVersion(ver.String())
+	return v
+}
+
+// Return the range of versions matching a version with an optional operator.
+func versionRangeFromVersion(v *Version) (*VersionRange, error) {
+	ver := v.parsed()
+	if ver == nil {
+		return &VersionRange{[]versionInterval{{}}}, nil
+	}
+
+	op := v.Op()
+	bound := versionBound{versionFromParsed(ver), true}
+	var interval versionInterval
+	switch op {
+	case OperatorNone, OperatorEqual:
+		interval = versionInterval{bound, bound}
+	case OperatorLess:
+		interval.upper = versionBound{bound.version, false}
+	case OperatorLessOrEqual:
+		interval.upper = bound
+	case OperatorGreater:
+		interval.lower = versionBound{bound.version, false}
+	case OperatorGreaterOrEqual:
+		interval.lower = bound
+	case OperatorApproximate:
+		ver.revision = maxRevision
+		interval = versionInterval{bound, versionBound{versionFromParsed(ver), true}}
+	default:
+		return nil, fmt.Errorf("unsupported version range operator: %s", v)
+	}
+	return &VersionRange{[]versionInterval{interval}}, nil
+}
+
+// Create a version range from the intersection of package dependencies, e.g.
+// ">=cat/pkg-1.2" and "<cat/pkg-2" result in the range ">=1.2,<2".
+// Unversioned dependencies match all versions.
+//
+// Only the versions of dependencies are considered, other restrictions such as
+// slots, USE dependencies, or repos are ignored. All dependencies must have the
+// same Cpn while blockers and glob versions are unsupported.
+func NewVersionRange(deps ...*Dep) (*VersionRange, error) {
+	r := &VersionRange{[]versionInterval{{}}}
+	for _, dep := range deps {
+		if dep.Cpn().Cmp(deps[0].Cpn()) != 0 {
+			return nil, fmt.Errorf("version range Cpn mismatch: %s != %s", dep.Cpn(), deps[0].Cpn())
+		} else if dep.Blocker() != BlockerNone {
+			return nil, fmt.Errorf("unsupported version range blocker: %s", dep)
+		}
+		other, err := versionRangeFromVersion(dep.Version())
+		if err != nil {
+			return nil, err
+		}
+		r = r.Intersection(other)
+	}
+	return r, nil
+}
+
+// Parse a string into a version range.
+//
+// Ranges are specified as groups of comma-separated versions with operators
+// that are intersected, e.g. ">=1.2,<2". Multiple groups separated by "|" are
+// unioned, e.g. ">=1.2,<2 | >=3". The string "*" matches all versions while
+// an empty string matches none.
+func ParseVersionRange(s string) (*VersionRange, error) {
+	r := &VersionRange{}
+	if strings.TrimSpace(s) == "" {
+		return r, nil
+	}
+
+	for _, group := range strings.Split(s, "|") {
+		g := &VersionRange{[]versionInterval{{}}}
+		for _, term := range strings.Split(group, ",") {
+			term = strings.TrimSpace(term)
+			if term == "*" {
+				continue
+			}
+			ver, err := NewVersionWithOp(term)
+			if err != nil {
+				return nil, fmt.Errorf("invalid version range: %s: %w", s, err)
+			}
+			other, err := versionRangeFromVersion(ver)
+			if err != nil {
+				return nil, fmt.Errorf("invalid version range: %s: %w", s, err)
+			}
+			g = g.Intersection(other)
+		}
+		r = r.Union(g)
+	}
+	return r, nil
+}
+
+// Sort and merge intervals, dropping empty ones.
+func newVersionRange(intervals []versionInterval) *VersionRange {
+	var vals []versionInterval
+	for _, interval := range intervals {
+		if !interval.empty() {
+			vals = append(vals, interval)
+		}
+	}
+	sort.SliceStable(vals, func(i, j int) bool {
+		return cmpLowerBounds(vals[i].lower, vals[j].lower) < 0
+	})
+
+	var merged []versionInterval
+	for _, interval := range vals {
+		if n := len(merged); n > 0 && boundsTouch(merged[n-1].upper, interval.lower) {
+			if cmpUpperBounds(interval.upper, merged[n-1].upper) > 0 {
+				merged[n-1].upper = interval.upper
+			}
+		} else {
+			merged = append(merged, interval)
+		}
+	}
+	return &VersionRange{merged}
+}
+
+// Return a new range containing the versions in both ranges.
+func (self *VersionRange) Intersection(other *VersionRange) *VersionRange {
+	var intervals []versionInterval
+	for _, a := range self.intervals {
+		for _, b := range other.intervals {
+			interval := a
+			if cmpLowerBounds(b.lower, a.lower) > 0 {
+				interval.lower = b.lower
+			}
+			if cmpUpperBounds(b.upper, a.upper) < 0 {
+				interval.upper = b.upper
+			}
+			intervals = append(intervals, interval)
+		}
+	}
+	return newVersionRange(intervals)
+}
+
+// Return a new range containing the versions in either range.
+func (self *VersionRange) Union(other *VersionRange) *VersionRange {
+	intervals := append(append([]versionInterval(nil), self.intervals...), other.intervals...)
+	return newVersionRange(intervals)
+}
+
+// Determine if a range contains a version, ignoring the version's operator.
+func (self *VersionRange) Contains(v *Version) bool {
+	for _, interval := range self.intervals {
+		if interval.contains(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Determine if a range contains no versions.
+//
+// Note that ranges only containing nonexistent versions between consecutive
+// revisions, e.g. ">1-r1,<1-r2", aren't considered empty.
+func (self *VersionRange) Empty() bool {
+	return len(self.intervals) == 0
+}
+
+// Return the minimal sets of package dependencies matching the range for a
+// given Cpn. Each set of dependencies matches a disjoint part of the range and
+// all dependencies in a set must match, e.g. the range ">=1,<2 | >=3" results
+// in [[>=cat/pkg-1, <cat/pkg-2], [>=cat/pkg-3]].
+func (self *VersionRange) Deps(cpn *Cpn) ([][]*Dep, error) {
+	var deps [][]*Dep
+	for _, interval := range self.intervals {
+		var set []*Dep
+		if vals := interval.strings(); vals == nil {
+			dep, err := NewDep(cpn.String())
+			if err != nil {
+				return nil, err
+			}
+			set = append(set, dep)
+		} else {
+			for _, s := range vals {
+				ver, err := NewVersionWithOp(s)
+				if err != nil {
+					return nil, err
+				}
+				op := ver.Op().String()
+				dep, err := NewDep(op + cpn.String() + "-" + strings.TrimPrefix(s, op))
+				if err != nil {
+					return nil, err
+				}
+				set = append(set, dep)
+			}
+		}
+		deps = append(deps, set)
+	}
+	return deps, nil
+}
+
+func (self *VersionRange) String() string {
+	var groups []string
+	for _, interval := range self.intervals {
+		if vals := interval.strings(); vals == nil {
+			groups = append(groups, "*")
+		} else {
+			groups = append(groups, strings.Join(vals, ","))
+		}
+	}
+	return strings.Join(groups, " | ")
+}
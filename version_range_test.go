@@ -0,0 +1,137 @@
+package pkgcraft_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/pkgcraft/pkgcraft-go"
+)
+
+func TestNewVersionRange(t *testing.T) {
+	d1, _ := NewDep(">=cat/pkg-1.2")
+	d2, _ := NewDep("<cat/pkg-2")
+	r, err := NewVersionRange(d1, d2)
+	assert.Nil(t, err)
+	assert.Equal(t, r.String(), ">=1.2,<2")
+
+	// unversioned deps match all versions
+	d3, _ := NewDep("cat/pkg:1")
+	r, err = NewVersionRange(d3)
+	assert.Nil(t, err)
+	assert.Equal(t, r.String(), "*")
+	r, err = NewVersionRange(d1, d3)
+	assert.Nil(t, err)
+	assert.Equal(t, r.String(), ">=1.2")
+
+	// disjoint deps
+	d4, _ := NewDep("<cat/pkg-1")
+	r, err = NewVersionRange(d1, d4)
+	assert.Nil(t, err)
+	assert.True(t, r.Empty())
+
+	// invalid
+	d5, _ := NewDep(">=a/b-1")
+	_, err = NewVersionRange(d1, d5)
+	assert.NotNil(t, err)
+	d6, _ := NewDep("=cat/pkg-1*")
+	_, err = NewVersionRange(d6)
+	assert.NotNil(t, err)
+
+	// blockers
+	for _, s := range []string{"!<cat/pkg-2", "!!>=cat/pkg-1", "!cat/pkg"} {
+		dep, _ := NewDep(s)
+		_, err = NewVersionRange(dep)
+		assert.NotNil(t, err, "%s didn't fail", s)
+		_, err = NewVersionRange(d1, dep)
+		assert.NotNil(t, err, "%s didn't fail", s)
+	}
+}
+
+func TestParseVersionRange(t *testing.T) {
+	for s, expected := range map[string]string{
+		"":                     "",
+		"*":                    "*",
+		">=1":                  ">=1",
+		">1 , <=2":             ">1,<=2",
+		">=1,<=1":              "=1",
+		"=1-r1":                "=1-r1",
+		"~1.2":                 "~1.2",
+		">=1.2,<2 | >=3":       ">=1.2,<2 | >=3",
+		">=3 | >=1.2,<2":       ">=1.2,<2 | >=3",
+		">=1,<2 | >=1.5,<3":    ">=1,<3",
+		"<1 | >=1":             "*",
+		"<1 | >1":              "<1 | >1",
+		">=2,<1":               "",
+		"~1 | =1-r3 | <1":      "<=1-r18446744073709551615",
+		">=1,<3 | >=2,<4 | <0": "<0 | >=1,<4",
+	} {
+		r, err := ParseVersionRange(s)
+		assert.Nil(t, err, "%q failed", s)
+		assert.Equal(t, r.String(), expected, "%q failed", s)
+	}
+
+	// invalid
+	for _, s := range []string{"1", ">=1,", "=1*", "a"} {
+		_, err := ParseVersionRange(s)
+		assert.NotNil(t, err, "%q didn't fail", s)
+	}
+}
+
+func TestVersionRangeOps(t *testing.T) {
+	r1, _ := ParseVersionRange(">=1,<3")
+	r2, _ := ParseVersionRange(">=2 | <0")
+
+	// intersection
+	assert.Equal(t, r1.Intersection(r2).String(), ">=2,<3")
+	assert.True(t, r1.Intersection(r2).Intersection(&VersionRange{}).Empty())
+
+	// union
+	assert.Equal(t, r1.Union(r2).String(), "<0 | >=1")
+
+	// containment
+	r, _ := ParseVersionRange("~1.2 | >=2,<3")
+	for _, s := range []string{"1.2", "1.2-r5", "2", "2.9", ">=2.5"} {
+		ver, _ := NewVersion(s)
+		assert.True(t, r.Contains(ver), "%s not in %s", s, r)
+	}
+	for _, s := range []string{"1.2.1", "1.3", "3", "0"} {
+		ver, _ := NewVersion(s)
+		assert.False(t, r.Contains(ver), "%s in %s", s, r)
+	}
+
+	// empty ranges
+	assert.True(t, (&VersionRange{}).Empty())
+	assert.False(t, r.Empty())
+}
+
+func TestVersionRangeDeps(t *testing.T) {
+	cpn, _ := NewCpn("cat/pkg")
+	r, _ := ParseVersionRange("<1 | ~1.2 | =1.5 | >=2,<3")
+	var sets [][]string
+	deps, err := r.Deps(cpn)
+	assert.Nil(t, err)
+	for _, group := range deps {
+		var set []string
+		for _, dep := range group {
+			set = append(set, dep.String())
+		}
+		sets = append(sets, set)
+	}
+	assert.Equal(t, sets, [][]string{
+		{"<cat/pkg-1"},
+		{"~cat/pkg-1.2"},
+		{"=cat/pkg-1.5"},
+		{">=cat/pkg-2", "<cat/pkg-3"},
+	})
+
+	// unbounded and empty ranges
+	r, _ = ParseVersionRange("*")
+	deps, err = r.Deps(cpn)
+	assert.Nil(t, err)
+	assert.Equal(t, len(deps), 1)
+	assert.Equal(t, deps[0][0].String(), "cat/pkg")
+	deps, err = (&VersionRange{}).Deps(cpn)
+	assert.Nil(t, err)
+	assert.Nil(t, deps)
+}
@@ -1,6 +1,7 @@
 package pkgcraft_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strings"
@@ -48,6 +49,59 @@ func TestVersion(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestOperatorFromString(t *testing.T) {
+	valid := map[string]Operator{
+		"<":  OperatorLess,
+		"<=": OperatorLessOrEqual,
+		"=":  OperatorEqual,
+		"=*": OperatorEqualGlob,
+		"~":  OperatorApproximate,
+		">=": OperatorGreaterOrEqual,
+		">":  OperatorGreater,
+	}
+	for s, expected := range valid {
+		op, err := OperatorFromString(s)
+		assert.Equal(t, op, expected)
+		assert.Nil(t, err)
+		assert.Equal(t, op.String(), s)
+	}
+
+	invalid := []string{"", "*", "<>", "=<", "a"}
+	for _, s := range invalid {
+		_, err := OperatorFromString(s)
+		assert.NotNil(t, err)
+	}
+}
+
+func TestNewVersionWithOp(t *testing.T) {
+	valid := map[string]Operator{
+		"<1":      OperatorLess,
+		"<=1-r1":  OperatorLessOrEqual,
+		"=1":      OperatorEqual,
+		"=1*":     OperatorEqualGlob,
+		"~1":      OperatorApproximate,
+		">=1.2_p": OperatorGreaterOrEqual,
+		">1":      OperatorGreater,
+	}
+	for s, op := range valid {
+		ver, err := NewVersionWithOp(s)
+		assert.Nil(t, err)
+		assert.Equal(t, ver.Op(), op)
+		assert.Equal(t, ver.String(), s)
+	}
+
+	// non-op versions
+	ver, _ := NewVersion("1")
+	assert.Equal(t, ver.Op(), OperatorNone)
+	assert.Equal(t, (&Version{}).Op(), OperatorNone)
+
+	// invalid
+	for _, s := range []string{"1", "", ">", "~1-r1", "<1*"} {
+		_, err := NewVersionWithOp(s)
+		assert.NotNil(t, err, "%s didn't fail", s)
+	}
+}
+
 func TestVersionCmp(t *testing.T) {
 	for _, s := range VERSION_TOML.Compares {
 		vals := strings.Fields(s)
@@ -107,6 +161,20 @@ func TestVersionIntersects(t *testing.T) {
 	v1, _ = NewVersion("0")
 	v2, _ = NewVersion("=0*")
 	assert.True(t, v1.Intersects(v2))
+
+	// op versions
+	for _, vals := range [][]string{{">1", "<2"}, {"<=1", ">=1"}, {"~1", "1-r5"}, {"<2", "<1"}} {
+		v1, _ = NewVersion(vals[0])
+		v2, _ = NewVersion(vals[1])
+		assert.True(t, v1.Intersects(v2), "%s doesn't intersect %s", v1, v2)
+		assert.True(t, v2.Intersects(v1), "%s doesn't intersect %s", v2, v1)
+	}
+	for _, vals := range [][]string{{"<1", ">=1"}, {">1", "<1"}, {"~1", "1.0"}} {
+		v1, _ = NewVersion(vals[0])
+		v2, _ = NewVersion(vals[1])
+		assert.False(t, v1.Intersects(v2), "%s intersects %s", v1, v2)
+		assert.False(t, v2.Intersects(v1), "%s intersects %s", v2, v1)
+	}
 }
 
 func TestVersionSort(t *testing.T) {
@@ -153,3 +221,80 @@ func BenchmarkVersionSort(b *testing.B) {
 	}
 	assert.Equal(b, versions[0].String(), "1")
 }
+
+func TestVersionMarshal(t *testing.T) {
+	ver, _ := NewVersion(">=1.2-r3")
+
+	// text
+	data, err := ver.MarshalText()
+	assert.Nil(t, err)
+	assert.Equal(t, string(data), ">=1.2-r3")
+	var v Version
+	assert.Nil(t, v.UnmarshalText(data))
+	assert.Equal(t, v.Cmp(ver), 0)
+
+	// JSON
+	data, err = json.Marshal(ver)
+	assert.Nil(t, err)
+	assert.Equal(t, string(data), `"\u003e=1.2-r3"`)
+	assert.Nil(t, json.Unmarshal(data, &v))
+	assert.Equal(t, v.String(), ">=1.2-r3")
+
+	// empty versions
+	data, err = json.Marshal(&Version{})
+	assert.Nil(t, err)
+	assert.Equal(t, string(data), `""`)
+	assert.Nil(t, json.Unmarshal(data, &v))
+	assert.Equal(t, v, Version{})
+
+	// invalid
+	assert.NotNil(t, json.Unmarshal([]byte(`"-1"`), &v))
+
+	// revisions
+	rev, _ := NewRevision("2")
+	data, err = json.Marshal(rev)
+	assert.Nil(t, err)
+	assert.Equal(t, string(data), `"2"`)
+	var r Revision
+	assert.Nil(t, json.Unmarshal(data, &r))
+	assert.Equal(t, r.Cmp(rev), 0)
+	data, _ = json.Marshal(&Revision{})
+	assert.Equal(t, string(data), `""`)
+	assert.NotNil(t, json.Unmarshal([]byte(`"a"`), &r))
+}
+
+func TestVersionSql(t *testing.T) {
+	ver, _ := NewVersion("1.2-r3")
+	val, err := ver.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, val, "1.2-r3")
+
+	var v Version
+	assert.Nil(t, v.Scan("1.2-r3"))
+	assert.Equal(t, v.Cmp(ver), 0)
+	assert.Nil(t, v.Scan([]byte("1.2-r3")))
+	assert.Equal(t, v.Cmp(ver), 0)
+
+	// NULL values
+	val, err = (&Version{}).Value()
+	assert.Nil(t, err)
+	assert.Nil(t, val)
+	assert.Nil(t, v.Scan(nil))
+	assert.Equal(t, v, Version{})
+
+	// invalid
+	assert.NotNil(t, v.Scan("-1"))
+	assert.NotNil(t, v.Scan(1))
+
+	// revisions
+	rev, _ := NewRevision("2")
+	val, err = rev.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, val, "2")
+	var r Revision
+	assert.Nil(t, r.Scan("2"))
+	assert.Equal(t, r.Cmp(rev), 0)
+	assert.Nil(t, r.Scan(nil))
+	assert.Equal(t, r, Revision{})
+	assert.NotNil(t, r.Scan("a"))
+}